@@ -0,0 +1,79 @@
+package mixorama
+
+import (
+	"os"
+
+	"github.com/go-audio/aiff"
+	"github.com/go-audio/audio"
+)
+
+// LoadAiff loads a .aiff file and returns its samples as []int16 (stereo) along with the sample rate.
+// If the file is mono, it converts it to stereo by duplicating the mono channel to both the left and right channels.
+// Samples with a bit depth other than 16 are scaled to 16 bits.
+func LoadAiff(filename string) ([]int16, int, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	decoder := aiff.NewDecoder(f)
+	intBuffer, err := decoder.FullPCMBuffer()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	bitDepth := intBuffer.SourceBitDepth
+	numChannels := intBuffer.Format.NumChannels
+
+	// toInt16 scales a sample from the bit depth of the file to 16 bits
+	toInt16 := func(sample int) int16 {
+		if bitDepth > 16 {
+			return int16(sample >> (bitDepth - 16))
+		}
+		return int16(sample << (16 - bitDepth))
+	}
+
+	l := len(intBuffer.Data)
+	if numChannels == 1 {
+		// Convert mono to stereo by duplicating the mono channel
+		stereoSamples := make([]int16, l*2)
+		for i := 0; i < l; i++ {
+			monoSample := toInt16(intBuffer.Data[i])
+			stereoSamples[2*i] = monoSample
+			stereoSamples[2*i+1] = monoSample
+		}
+		return stereoSamples, intBuffer.Format.SampleRate, nil
+	}
+
+	samples := make([]int16, l)
+	for i := 0; i < l; i++ {
+		samples[i] = toInt16(intBuffer.Data[i])
+	}
+
+	return samples, intBuffer.Format.SampleRate, nil
+}
+
+// SaveAiff saves a slice of interleaved int16 samples with the given number of channels as a 16-bit .aiff file
+func SaveAiff(filename string, samples []int16, sampleRate, numChannels int) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	encoder := aiff.NewEncoder(f, sampleRate, 16, numChannels)
+	intBuffer := &audio.IntBuffer{
+		Data:           make([]int, len(samples)),
+		Format:         &audio.Format{SampleRate: sampleRate, NumChannels: numChannels},
+		SourceBitDepth: 16,
+	}
+	for i, sample := range samples {
+		intBuffer.Data[i] = int(sample)
+	}
+
+	if err := encoder.Write(intBuffer); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
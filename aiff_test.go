@@ -0,0 +1,54 @@
+package mixorama
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveAndLoadAiff(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.aiff")
+	samples := []int16{1000, -1000, 2000, -2000, 32767, -32768}
+
+	if err := SaveAiff(filename, samples, 48000, 2); err != nil {
+		t.Fatalf("Failed to save AIFF file: %v", err)
+	}
+
+	loaded, sampleRate, err := LoadAiff(filename)
+	if err != nil {
+		t.Fatalf("Failed to load AIFF file: %v", err)
+	}
+	if sampleRate != 48000 {
+		t.Errorf("Expected sample rate 48000, got %d", sampleRate)
+	}
+	if len(loaded) != len(samples) {
+		t.Fatalf("Expected %d samples, got %d", len(samples), len(loaded))
+	}
+	for i, v := range loaded {
+		if v != samples[i] {
+			t.Errorf("Expected sample %d to be %d, got %d", i, samples[i], v)
+		}
+	}
+}
+
+func TestLoadAiffMono(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "mono.aiff")
+	samples := []int16{100, -200, 300}
+
+	if err := SaveAiff(filename, samples, 44100, 1); err != nil {
+		t.Fatalf("Failed to save AIFF file: %v", err)
+	}
+
+	loaded, _, err := LoadAudio(filename)
+	if err != nil {
+		t.Fatalf("Failed to load AIFF file: %v", err)
+	}
+	expected := []int16{100, 100, -200, -200, 300, 300}
+	if len(loaded) != len(expected) {
+		t.Fatalf("Expected %d samples, got %d", len(expected), len(loaded))
+	}
+	for i, v := range loaded {
+		if v != expected[i] {
+			t.Errorf("Expected sample %d to be %d, got %d", i, expected[i], v)
+		}
+	}
+}
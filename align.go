@@ -0,0 +1,42 @@
+package mixorama
+
+import "math/cmplx"
+
+// FindAlignmentOffset uses cross-correlation to find how many samples other is delayed relative to reference.
+// A positive offset means that other starts later than reference, so other[i+offset] lines up with reference[i].
+// A negative offset means that other starts earlier. If either slice is empty, 0 is returned.
+func FindAlignmentOffset(reference, other []int16) int {
+	if len(reference) == 0 || len(other) == 0 {
+		return 0
+	}
+
+	// Zero-pad so that the circular correlation does not wrap around
+	n := nextPowerOfTwo(len(reference) + len(other) - 1)
+	a := make([]complex128, n)
+	b := make([]complex128, n)
+	for i, v := range reference {
+		a[i] = complex(float64(v), 0)
+	}
+	for i, v := range other {
+		b[i] = complex(float64(v), 0)
+	}
+	fft(a)
+	fft(b)
+	for i := range a {
+		a[i] = cmplx.Conj(a[i]) * b[i]
+	}
+	ifft(a)
+
+	bestOffset, bestCorrelation := 0, real(a[0])
+	for lag := -(len(reference) - 1); lag < len(other); lag++ {
+		index := lag
+		if index < 0 {
+			index += n
+		}
+		if correlation := real(a[index]); correlation > bestCorrelation {
+			bestOffset, bestCorrelation = lag, correlation
+		}
+	}
+
+	return bestOffset
+}
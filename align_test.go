@@ -0,0 +1,29 @@
+package mixorama
+
+import "testing"
+
+func TestFindAlignmentOffset(t *testing.T) {
+	reference := createNoise(10000, 4000, 1)
+
+	// Delay the reference by a known number of samples, and add some unrelated noise
+	delay := 137
+	delayed, err := LinearSummation(Concat(make([]int16, delay), reference), createNoise(2000, len(reference)+delay, 2))
+	if err != nil {
+		t.Fatalf("Error in LinearSummation: %v", err)
+	}
+	if offset := FindAlignmentOffset(reference, delayed); offset != delay {
+		t.Errorf("Expected an offset of %d, got %d", delay, offset)
+	}
+
+	// The other track starts earlier than the reference
+	if offset := FindAlignmentOffset(delayed, reference); offset != -delay {
+		t.Errorf("Expected an offset of %d, got %d", -delay, offset)
+	}
+
+	if offset := FindAlignmentOffset(reference, reference); offset != 0 {
+		t.Errorf("Expected an offset of 0 for identical tracks, got %d", offset)
+	}
+	if offset := FindAlignmentOffset(nil, reference); offset != 0 {
+		t.Errorf("Expected an offset of 0 for empty input, got %d", offset)
+	}
+}
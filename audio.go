@@ -0,0 +1,59 @@
+package mixorama
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hajimehoshi/go-mp3"
+)
+
+// LoadAudio loads an audio file and returns its samples as []int16 (stereo) along with the sample rate.
+// The file format is selected by the file extension. Supported extensions are .wav, .mp3, .flac, .aiff and .ogg.
+func LoadAudio(filename string) ([]int16, int, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".wav", ".wave":
+		return LoadWav(filename)
+	case ".mp3":
+		return LoadMp3(filename)
+	case ".flac":
+		return LoadFlac(filename)
+	case ".aif", ".aiff":
+		return LoadAiff(filename)
+	case ".ogg":
+		return LoadOgg(filename)
+	}
+	return nil, 0, fmt.Errorf("unsupported audio file format: %s", filename)
+}
+
+// LoadMp3 loads a .mp3 file and returns its samples as []int16 (stereo) along with the sample rate.
+// The decoded samples are always stereo, also for mono files.
+func LoadMp3(filename string) ([]int16, int, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	decoder, err := mp3.NewDecoder(f)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// The decoder outputs 16-bit little endian interleaved stereo samples
+	data, err := io.ReadAll(decoder)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	l := len(data) / 2
+	stereoSamples := make([]int16, l)
+	for i := 0; i < l; i++ {
+		stereoSamples[i] = int16(binary.LittleEndian.Uint16(data[2*i:]))
+	}
+
+	return stereoSamples, decoder.SampleRate(), nil
+}
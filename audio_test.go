@@ -0,0 +1,38 @@
+package mixorama
+
+import "testing"
+
+func TestLoadMp3(t *testing.T) {
+	samples, sampleRate, err := LoadMp3("test.mp3")
+	if err != nil {
+		t.Fatalf("Failed to load test.mp3: %v", err)
+	}
+	if sampleRate != 44100 {
+		t.Errorf("Expected sample rate 44100, got %d", sampleRate)
+	}
+	if len(samples) == 0 {
+		t.Error("Expected samples to be decoded")
+	}
+	if len(samples)%2 != 0 {
+		t.Errorf("Expected interleaved stereo samples, got an odd number of samples: %d", len(samples))
+	}
+}
+
+func TestLoadAudio(t *testing.T) {
+	for _, filename := range []string{"test.wav", "test.mp3"} {
+		samples, sampleRate, err := LoadAudio(filename)
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", filename, err)
+		}
+		if sampleRate <= 0 {
+			t.Errorf("Expected valid sample rate for %s, got %d", filename, sampleRate)
+		}
+		if len(samples) == 0 {
+			t.Errorf("Expected samples to be decoded from %s", filename)
+		}
+	}
+
+	if _, _, err := LoadAudio("test.xyz"); err == nil {
+		t.Error("Expected error for an unsupported file extension")
+	}
+}
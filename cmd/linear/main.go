@@ -1,10 +1,10 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"log"
-	"math"
 
 	"github.com/xyproto/mixorama"
 )
@@ -39,59 +39,58 @@ func main() {
 		return
 	}
 
-	// Load the first input file to initialize the combined samples and sample rate
+	// Load the first input file to find the sample rate
 	inputFiles := flag.Args()
 	firstFile := inputFiles[0]
-	combined, sampleRate, err := mixorama.LoadWav(firstFile)
+	first, sampleRate, err := mixorama.LoadWav(firstFile)
 	if err != nil {
 		log.Fatalf("Failed to load %s: %v", firstFile, err)
 	}
 
-	// Find the loudest peak across all input files
-	loudestPeak := mixorama.FindPeakAmplitude(combined)
+	mixer := mixorama.NewMixer(sampleRate)
+	if err := mixer.AddTrack(first); err != nil {
+		log.Fatalf("Failed to add %s: %v", firstFile, err)
+	}
 
-	// Process additional files and mix them using weighted summation
+	// Add the remaining files, which must have the same sample rate
 	for _, inputFile := range inputFiles[1:] {
-		// Load the next file
-		wave, sr, err := mixorama.LoadWav(inputFile)
-		if err != nil {
-			log.Fatalf("Failed to load %s: %v", inputFile, err)
-		}
-
-		// Ensure the sample rate matches
-		if sr != sampleRate {
-			log.Fatalf("Sample rate mismatch between %s and %s", firstFile, inputFile)
+		if err := mixer.AddWav(inputFile); err != nil {
+			log.Fatalln(err)
 		}
+	}
 
-		// Find the peak amplitude in the current file and track the loudest peak
-		peak := mixorama.FindPeakAmplitude(wave)
-		if peak > loudestPeak {
+	// Find the loudest peak across all input files
+	loudestPeak := int16(0)
+	for _, track := range mixer.Tracks() {
+		if peak := mixorama.FindPeakAmplitude(track); peak > loudestPeak {
 			loudestPeak = peak
 		}
+	}
 
-		// Pad the shorter sample with zeros
-		combined, wave = mixorama.PadSamples(combined, wave)
-
-		// Perform weighted summation (reduce contribution of each input to avoid clipping)
-		for i := 0; i < len(combined); i++ {
-			weightedSum := (int32(combined[i]) + int32(wave[i])) / 2 // Adjust weight (averaging)
-			if weightedSum > math.MaxInt16 {
-				combined[i] = math.MaxInt16
-			} else if weightedSum < math.MinInt16 {
-				combined[i] = math.MinInt16
-			} else {
-				combined[i] = int16(weightedSum)
-			}
-		}
+	// Perform weighted summation (reduce contribution of each input to avoid clipping)
+	combined, err := mixer.Mix(mixorama.MixWeighted)
+	if err != nil {
+		log.Fatalf("Error during mixing: %v", err)
+	}
+
+	// Warn if the mixed samples were clipped
+	if clipped := mixorama.CountClippedSamples(combined); clipped > 0 {
+		fmt.Printf("Warning: %d samples were clipped during mixing\n", clipped)
 	}
 
 	// Apply low-pass filter using a reasonable cutoff frequency (e.g., 15kHz to remove high-frequency noise)
 	fmt.Println("Applying low-pass filter to combined audio.")
-	combined = mixorama.LowPassFilter(combined, sampleRate, 15000) // Cut off frequencies above 15kHz
+	combined = mixorama.LowPassFilterChannels(combined, sampleRate, 15000, 2) // Cut off frequencies above 15kHz
 
 	// Normalize the final combined samples based on the loudest peak value
 	fmt.Printf("Normalizing combined file to match the loudest input peak: %d\n", loudestPeak)
-	combined = mixorama.NormalizeSamples(combined, loudestPeak)
+	if normalized, err := mixorama.NormalizeSamplesErr(combined, loudestPeak); err == nil {
+		combined = normalized
+	} else if errors.Is(err, mixorama.ErrSilentInput) {
+		fmt.Println("Warning: the combined audio is silent, skipping normalization")
+	} else {
+		log.Fatalf("Failed to normalize: %v", err)
+	}
 
 	// Save the final combined result to the output file
 	if err := mixorama.SaveWav(*outputFile, combined, sampleRate); err != nil {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -38,60 +39,62 @@ func main() {
 		return
 	}
 
-	// Load the first input file to initialize the combined samples and sample rate
+	// Load the first input file to find the sample rate
 	inputFiles := flag.Args()
 	firstFile := inputFiles[0]
-	combined, sampleRate, err := mixorama.LoadWav(firstFile)
+	first, sampleRate, err := mixorama.LoadWav(firstFile)
 	if err != nil {
 		log.Fatalf("Failed to load %s: %v", firstFile, err)
 	}
 
-	// Find the highest frequency across all files (initialize to 0) and track the loudest sample
-	highestFrequency := 0.0
-	loudestPeak := mixorama.FindPeakAmplitude(combined)
-
-	// Process additional files and mix them using RMSMixing
-	for _, inputFile := range inputFiles {
-		// Load the next file
-		wave, sr, err := mixorama.LoadWav(inputFile)
-		if err != nil {
-			log.Fatalf("Failed to load %s: %v", inputFile, err)
-		}
+	mixer := mixorama.NewMixer(sampleRate)
+	if err := mixer.AddTrack(first); err != nil {
+		log.Fatalf("Failed to add %s: %v", firstFile, err)
+	}
 
-		// Ensure the sample rate matches
-		if sr != sampleRate {
-			log.Fatalf("Sample rate mismatch between %s and %s", firstFile, inputFile)
+	// Add the remaining files, which must have the same sample rate
+	for _, inputFile := range inputFiles[1:] {
+		if err := mixer.AddWav(inputFile); err != nil {
+			log.Fatalln(err)
 		}
+	}
 
-		// Determine the highest frequency in the current file
-		currentHighestFrequency := mixorama.AnalyzeHighestFrequency(wave, sr)
-		if currentHighestFrequency > highestFrequency {
-			highestFrequency = currentHighestFrequency
+	// Find the highest frequency and the loudest peak across all files
+	highestFrequency := 0.0
+	loudestPeak := int16(0)
+	for _, track := range mixer.Tracks() {
+		if frequency := mixorama.AnalyzeHighestFrequency(track, sampleRate); frequency > highestFrequency {
+			highestFrequency = frequency
 		}
-
-		// Find the peak amplitude in the current file and track the loudest peak
-		peak := mixorama.FindPeakAmplitude(wave)
-		if peak > loudestPeak {
+		if peak := mixorama.FindPeakAmplitude(track); peak > loudestPeak {
 			loudestPeak = peak
 		}
+	}
 
-		// Pad the shorter sample with zeros
-		combined, wave = mixorama.PadSamples(combined, wave)
+	// Mix all the tracks using RMS mixing
+	combined, err := mixer.Mix(mixorama.MixRMS)
+	if err != nil {
+		log.Fatalf("Error during RMS mixing: %v", err)
+	}
 
-		// Mix the current combined samples with the newly loaded samples
-		combined, err = mixorama.RMSMixing(combined, wave)
-		if err != nil {
-			log.Fatalf("Error during RMS mixing of %s: %v", inputFile, err)
-		}
+	// Warn if the mixed samples were clipped
+	if clipped := mixorama.CountClippedSamples(combined); clipped > 0 {
+		fmt.Printf("Warning: %d samples were clipped during mixing\n", clipped)
 	}
 
 	// Apply low-pass filter using the highest detected frequency
 	fmt.Printf("Applying low-pass filter with cutoff frequency: %.2f Hz\n", highestFrequency)
-	combined = mixorama.LowPassFilter(combined, sampleRate, highestFrequency)
+	combined = mixorama.LowPassFilterChannels(combined, sampleRate, highestFrequency, 2)
 
 	// Normalize the final combined samples to the loudest input sample's peak
 	fmt.Printf("Normalizing loudness to the loudest peak: %d\n", loudestPeak)
-	combined = mixorama.NormalizeSamples(combined, loudestPeak)
+	if normalized, err := mixorama.NormalizeSamplesErr(combined, loudestPeak); err == nil {
+		combined = normalized
+	} else if errors.Is(err, mixorama.ErrSilentInput) {
+		fmt.Println("Warning: the combined audio is silent, skipping normalization")
+	} else {
+		log.Fatalf("Failed to normalize: %v", err)
+	}
 
 	// Save the final combined result to the output file
 	if err := mixorama.SaveWav(*outputFile, combined, sampleRate); err != nil {
@@ -0,0 +1,88 @@
+package mixorama
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+const (
+	// denoiseFrameMs is the minimum length of the frames that DenoiseSpectral works on
+	denoiseFrameMs = 40
+
+	// denoiseOverSubtraction is how many times the noise spectrum is subtracted, to also remove noise peaks
+	denoiseOverSubtraction = 1.5
+
+	// denoiseSpectralFloor is the smallest fraction of the magnitude that is kept in each bin,
+	// which reduces the "musical noise" that spectral subtraction can leave behind
+	denoiseSpectralFloor = 0.05
+)
+
+// noiseSpectrum returns the average magnitude of each bin over the Hann-windowed frames of the noise samples
+func noiseSpectrum(noise []int16, frameSize, hopSize int) []float64 {
+	window := hannWindow(frameSize)
+	magnitudes := make([]float64, frameSize)
+	numFrames := 0
+	frame := make([]complex128, frameSize)
+	for start := 0; start == 0 || start+frameSize <= len(noise); start += hopSize {
+		for i := 0; i < frameSize; i++ {
+			value := 0.0
+			if start+i < len(noise) {
+				value = float64(noise[start+i])
+			}
+			frame[i] = complex(value*window[i], 0)
+		}
+		fft(frame)
+		for i, bin := range frame {
+			magnitudes[i] += cmplx.Abs(bin)
+		}
+		numFrames++
+	}
+	for i := range magnitudes {
+		magnitudes[i] /= float64(numFrames)
+	}
+	return magnitudes
+}
+
+// DenoiseSpectral reduces steady background noise with spectral subtraction. The average spectrum of noiseProfile,
+// which should contain only the noise, is subtracted from the spectrum of each frame of the samples, keeping the phase,
+// and the frames are reconstructed with overlap-add. If noiseProfile is empty, an unchanged copy of the samples is returned.
+func DenoiseSpectral(samples []int16, sampleRate int, noiseProfile []int16) []int16 {
+	if len(noiseProfile) == 0 || sampleRate <= 0 {
+		denoised := make([]int16, len(samples))
+		copy(denoised, samples)
+		return denoised
+	}
+
+	frameSize := nextPowerOfTwo(sampleRate * denoiseFrameMs / 1000)
+	if frameSize < 4 {
+		frameSize = 4
+	}
+	hopSize := frameSize / 4
+	noise := noiseSpectrum(noiseProfile, frameSize, hopSize)
+
+	subtract := func(frame []float64) []float64 {
+		spectrum := make([]complex128, len(frame))
+		for i, value := range frame {
+			spectrum[i] = complex(value, 0)
+		}
+		fft(spectrum)
+		for i, bin := range spectrum {
+			magnitude := cmplx.Abs(bin)
+			if magnitude == 0 {
+				continue
+			}
+			reduced := math.Max(magnitude-denoiseOverSubtraction*noise[i], denoiseSpectralFloor*magnitude)
+			spectrum[i] = bin * complex(reduced/magnitude, 0)
+		}
+		ifft(spectrum)
+		processed := make([]float64, len(frame))
+		for i, bin := range spectrum {
+			processed[i] = real(bin)
+		}
+		return processed
+	}
+
+	// The frame size and hop size are always valid, so ProcessOverlapAdd can not fail
+	denoised, _ := ProcessOverlapAdd(samples, frameSize, hopSize, 0, subtract)
+	return denoised
+}
@@ -0,0 +1,49 @@
+package mixorama
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// createNoise returns uniform white noise with the given amplitude, using a fixed seed
+func createNoise(amplitude float64, numSamples int, seed int64) []int16 {
+	r := rand.New(rand.NewSource(seed))
+	noise := make([]int16, numSamples)
+	for i := range noise {
+		noise[i] = int16(amplitude * (2*r.Float64() - 1))
+	}
+	return noise
+}
+
+// snr returns the signal-to-noise ratio in dB of the processed samples, compared to the clean signal
+func snr(clean, processed []int16) float64 {
+	signalPower, noisePower := 0.0, 0.0
+	for i := range clean {
+		difference := float64(processed[i]) - float64(clean[i])
+		signalPower += float64(clean[i]) * float64(clean[i])
+		noisePower += difference * difference
+	}
+	return 10 * math.Log10(signalPower/noisePower)
+}
+
+func TestDenoiseSpectral(t *testing.T) {
+	sampleRate := 44100
+	tone := createSineWave(1000, 8000, sampleRate, sampleRate)
+	noisy, err := LinearSummation(tone, createNoise(3000, sampleRate, 1))
+	if err != nil {
+		t.Fatalf("Error in LinearSummation: %v", err)
+	}
+	noiseProfile := createNoise(3000, sampleRate/2, 2)
+
+	denoised := DenoiseSpectral(noisy, sampleRate, noiseProfile)
+	if len(denoised) != len(noisy) {
+		t.Fatalf("Expected %d samples, got %d", len(noisy), len(denoised))
+	}
+
+	before := snr(tone, noisy)
+	after := snr(tone, denoised)
+	if after < before+6 {
+		t.Errorf("Expected the SNR to improve by at least 6 dB, went from %.2f dB to %.2f dB", before, after)
+	}
+}
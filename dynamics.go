@@ -0,0 +1,182 @@
+package mixorama
+
+import "math"
+
+// Limiter applies smooth gain reduction so that the absolute amplitude never exceeds the threshold.
+// The gain is reduced immediately when a sample would exceed the threshold, and then released
+// gradually back towards unity gain, with a time constant of releaseSamples samples.
+// If threshold is not positive, an unchanged copy of the samples is returned.
+func Limiter(samples []int16, threshold int16, releaseSamples int) []int16 {
+	limitedSamples := make([]int16, len(samples))
+	if threshold <= 0 {
+		copy(limitedSamples, samples)
+		return limitedSamples
+	}
+
+	release := 0.0
+	if releaseSamples > 0 {
+		release = math.Exp(-1.0 / float64(releaseSamples))
+	}
+
+	gain := 1.0
+	for i, sample := range samples {
+		// Find the gain needed to keep this sample below the threshold
+		abs := math.Abs(float64(sample))
+		requiredGain := 1.0
+		if abs > float64(threshold) {
+			requiredGain = float64(threshold) / abs
+		}
+
+		if requiredGain < gain {
+			// Attack immediately, to avoid any overshoot
+			gain = requiredGain
+		} else {
+			// Release gradually towards the required gain
+			gain = requiredGain + (gain-requiredGain)*release
+		}
+
+		limited := float64(sample) * gain
+		// Truncate towards zero, so that rounding never pushes a sample above the threshold
+		limitedSamples[i] = int16(limited)
+	}
+
+	return limitedSamples
+}
+
+// timeCoefficient returns the smoothing coefficient of an envelope follower with the given time constant
+func timeCoefficient(timeMs float64, sampleRate int) float64 {
+	if timeMs <= 0 {
+		return 0
+	}
+	return math.Exp(-1.0 / (timeMs / 1000 * float64(sampleRate)))
+}
+
+// Compress reduces the dynamic range of the samples with a feed-forward compressor.
+// When the envelope of the signal rises above threshold (in dBFS), the level above the threshold is divided by ratio,
+// so a ratio of 4 turns 8 dB above the threshold into 2 dB. attackMs and releaseMs are the time constants
+// of the envelope follower when the signal rises and falls.
+func Compress(samples []int16, sampleRate int, threshold float64, ratio float64, attackMs, releaseMs float64) []int16 {
+	compressedSamples := make([]int16, len(samples))
+	if ratio < 1 {
+		ratio = 1
+	}
+
+	attack := timeCoefficient(attackMs, sampleRate)
+	release := timeCoefficient(releaseMs, sampleRate)
+
+	envelope := 0.0
+	for i, sample := range samples {
+		abs := math.Abs(float64(sample))
+		if abs > envelope {
+			envelope = abs + (envelope-abs)*attack
+		} else {
+			envelope = abs + (envelope-abs)*release
+		}
+
+		gain := 1.0
+		if envelope > 0 {
+			levelDB := 20 * math.Log10(envelope/math.MaxInt16)
+			if levelDB > threshold {
+				gainDB := (threshold - levelDB) * (1 - 1/ratio)
+				gain = math.Pow(10, gainDB/20)
+			}
+		}
+
+		compressedSamples[i] = clampToInt16(float64(sample) * gain)
+	}
+
+	return compressedSamples
+}
+
+// maxAutoGain is the largest gain that AutoGainControl applies, so that background noise is not boosted too much
+const maxAutoGain = 10.0
+
+// AutoGainControl adjusts the gain of the samples block by block, so that the RMS level of each block of windowMs
+// milliseconds moves towards targetRMS. The gain is interpolated between the centers of the blocks, to avoid
+// pumping, and is limited to maxAutoGain. Silent blocks keep the gain of the previous block.
+func AutoGainControl(samples []int16, sampleRate int, targetRMS float64, windowMs float64) []int16 {
+	l := len(samples)
+	adjustedSamples := make([]int16, l)
+	if l == 0 {
+		return adjustedSamples
+	}
+
+	blockSize := int(windowMs / 1000 * float64(sampleRate))
+	if blockSize < 1 {
+		blockSize = 1
+	}
+	numBlocks := (l + blockSize - 1) / blockSize
+
+	// Find the gain and the center of each block
+	gains := make([]float64, numBlocks)
+	centers := make([]float64, numBlocks)
+	gain := 1.0
+	for b := 0; b < numBlocks; b++ {
+		start := b * blockSize
+		end := start + blockSize
+		if end > l {
+			end = l
+		}
+		if rms := RMSLevel(samples[start:end]); rms > 0 {
+			gain = math.Min(targetRMS/rms, maxAutoGain)
+		}
+		gains[b] = gain
+		centers[b] = float64(start+end-1) / 2
+	}
+
+	b := 0
+	for i := 0; i < l; i++ {
+		position := float64(i)
+		for b < numBlocks-1 && position > centers[b+1] {
+			b++
+		}
+		gain := gains[b]
+		if b < numBlocks-1 && position > centers[b] {
+			frac := (position - centers[b]) / (centers[b+1] - centers[b])
+			gain += frac * (gains[b+1] - gains[b])
+		}
+		adjustedSamples[i] = clampToInt16(float64(samples[i]) * gain)
+	}
+
+	return adjustedSamples
+}
+
+// Duck lowers the music by reductionDB while the voice is louder than thresholdDB (in dBFS), and mixes the two.
+// The level of the voice is followed with an envelope, and the gain of the music moves towards the reduced gain
+// with the attackMs time constant, and back towards unity gain with the releaseMs time constant.
+// The shorter of music and voice is padded with silence, and the mix is clamped to the int16 range.
+func Duck(music, voice []int16, sampleRate int, thresholdDB, reductionDB, attackMs, releaseMs float64) []int16 {
+	padded := padToLongest([][]int16{music, voice})
+	music, voice = padded[0], padded[1]
+
+	attack := timeCoefficient(attackMs, sampleRate)
+	release := timeCoefficient(releaseMs, sampleRate)
+	threshold := math.MaxInt16 * math.Pow(10, thresholdDB/20)
+	reducedGain := math.Pow(10, -math.Abs(reductionDB)/20)
+
+	mixed := make([]int16, len(music))
+	envelope := 0.0
+	gain := 1.0
+	for i := range music {
+		abs := math.Abs(float64(voice[i]))
+		if abs > envelope {
+			envelope = abs + (envelope-abs)*attack
+		} else {
+			envelope = abs + (envelope-abs)*release
+		}
+
+		targetGain := 1.0
+		if envelope > threshold {
+			targetGain = reducedGain
+		}
+		if targetGain < gain {
+			gain = targetGain + (gain-targetGain)*attack
+		} else {
+			gain = targetGain + (gain-targetGain)*release
+		}
+
+		mixed[i] = clampToInt16(float64(music[i])*gain + float64(voice[i]))
+	}
+
+	return mixed
+}
@@ -0,0 +1,117 @@
+package mixorama
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLimiter(t *testing.T) {
+	// A quiet section, a loud section that exceeds the threshold and another quiet section
+	samples := append(createTestWaveform(5000, 1000), createTestWaveform(30000, 1000)...)
+	samples = append(samples, createTestWaveform(-5000, 5000)...)
+
+	threshold := int16(20000)
+	limited := Limiter(samples, threshold, 100)
+
+	if len(limited) != len(samples) {
+		t.Fatalf("Expected %d samples, got %d", len(samples), len(limited))
+	}
+
+	for i, v := range limited {
+		if v > threshold || v < -threshold {
+			t.Fatalf("Expected the output to stay within the threshold, got %d at index %d", v, i)
+		}
+	}
+
+	// The quiet section before the loud section should be untouched
+	if limited[999] != 5000 {
+		t.Errorf("Expected the quiet section to be untouched, got %d", limited[999])
+	}
+
+	// The gain should be reduced right after the loud section, and then recover
+	if limited[2000] < -4000 {
+		t.Errorf("Expected reduced gain right after the loud section, got %d", limited[2000])
+	}
+	if last := limited[len(limited)-1]; last > -4990 {
+		t.Errorf("Expected the gain to recover after the loud section, got %d", last)
+	}
+}
+
+func TestCompress(t *testing.T) {
+	sampleRate := 44100
+	loud := createSineWave(440, 30000, sampleRate, sampleRate/10)
+	quiet := createSineWave(440, 1000, sampleRate, sampleRate/2)
+	samples := append(loud, quiet...)
+
+	compressed := Compress(samples, sampleRate, -20, 4, 1, 50)
+	if len(compressed) != len(samples) {
+		t.Fatalf("Expected %d samples, got %d", len(samples), len(compressed))
+	}
+
+	// The loud part is 19 dB above the threshold, so it should be reduced by about 14 dB
+	loudPeak := FindPeakAmplitude(compressed[sampleRate/20 : sampleRate/10])
+	if loudPeak > 10000 {
+		t.Errorf("Expected the loud part to be attenuated, got peak %d", loudPeak)
+	}
+
+	// The quiet part is below the threshold, so once the compressor has released it should be left mostly intact
+	quietPeak := FindPeakAmplitude(compressed[len(compressed)-sampleRate/10:])
+	if quietPeak < 950 || quietPeak > 1000 {
+		t.Errorf("Expected the quiet part to be left mostly intact, got peak %d", quietPeak)
+	}
+}
+
+func TestAutoGainControl(t *testing.T) {
+	sampleRate := 44100
+	quiet := createSineWave(441, 1000, sampleRate, sampleRate)
+	loud := createSineWave(441, 16000, sampleRate, sampleRate)
+	samples := Concat(quiet, loud)
+
+	adjusted := AutoGainControl(samples, sampleRate, 5000, 100)
+	if len(adjusted) != len(samples) {
+		t.Fatalf("Expected %d samples, got %d", len(samples), len(adjusted))
+	}
+
+	inputRatio := RMSLevel(samples[sampleRate:]) / RMSLevel(samples[:sampleRate])
+	outputRatio := RMSLevel(adjusted[sampleRate:]) / RMSLevel(adjusted[:sampleRate])
+	if outputRatio > 1.5 || outputRatio < 1/1.5 {
+		t.Errorf("Expected the two halves to have about the same RMS level, got a ratio of %.2f (was %.2f)", outputRatio, inputRatio)
+	}
+	if rms := RMSLevel(adjusted[sampleRate/2 : sampleRate-sampleRate/10]); math.Abs(rms-5000) > 250 {
+		t.Errorf("Expected the RMS level of the quiet half to be about 5000, got %.2f", rms)
+	}
+}
+
+func TestDuck(t *testing.T) {
+	sampleRate := 44100
+	music := createSineWave(220, 8000, sampleRate, 3*sampleRate)
+	voice := make([]int16, 3*sampleRate)
+	copy(voice[sampleRate:], createSineWave(1000, 8000, sampleRate, sampleRate))
+
+	mixed := Duck(music, voice, sampleRate, -30, 12, 10, 50)
+	if len(mixed) != len(music) {
+		t.Fatalf("Expected %d samples, got %d", len(music), len(mixed))
+	}
+
+	// Remove the voice again, so that only the ducked music is left
+	ducked := make([]int16, len(mixed))
+	for i := range mixed {
+		ducked[i] = mixed[i] - voice[i]
+	}
+
+	before := RMSLevel(ducked[:sampleRate])
+	during := RMSLevel(ducked[sampleRate+sampleRate/10 : 2*sampleRate])
+	after := RMSLevel(ducked[2*sampleRate+sampleRate*9/10:])
+	musicLevel := RMSLevel(music)
+
+	if math.Abs(before-musicLevel) > 1 {
+		t.Errorf("Expected the music to be unchanged before the voice, got RMS %.2f instead of %.2f", before, musicLevel)
+	}
+	// 12 dB is a factor of about 4
+	if math.Abs(during-musicLevel/4) > 100 {
+		t.Errorf("Expected the music to be reduced by 12 dB while the voice is active, got RMS %.2f", during)
+	}
+	if math.Abs(after-musicLevel) > 50 {
+		t.Errorf("Expected the music to be restored after the voice, got RMS %.2f instead of %.2f", after, musicLevel)
+	}
+}
@@ -0,0 +1,384 @@
+package mixorama
+
+import "math"
+
+// maxDelayRepeats limits how many echoes Delay includes in the tail
+const maxDelayRepeats = 100
+
+// Delay adds echoes to the samples, repeating every delayMs milliseconds. Each echo is scaled by feedback (0-1)
+// compared to the previous one, and mix (0-1) sets the balance between the dry and the delayed signal.
+// The output is extended beyond the input so that it includes the decaying tail of echoes.
+func Delay(samples []int16, sampleRate int, delayMs float64, feedback float64, mix float64) []int16 {
+	delaySamples := int(math.Round(delayMs * float64(sampleRate) / 1000))
+	if delaySamples <= 0 || len(samples) == 0 {
+		delayedSamples := make([]int16, len(samples))
+		copy(delayedSamples, samples)
+		return delayedSamples
+	}
+
+	// Feedback must be below 1 for the echoes to decay
+	feedback = math.Max(0, math.Min(feedback, 0.99))
+	mix = math.Max(0, math.Min(mix, 1))
+
+	// Find how many echoes it takes before they are quieter than the smallest sample value
+	repeats := 1
+	if peak := float64(FindPeakAmplitude(samples)); feedback > 0 && peak > 1 {
+		repeats = int(math.Ceil(math.Log(1/peak)/math.Log(feedback))) + 1
+		if repeats > maxDelayRepeats {
+			repeats = maxDelayRepeats
+		}
+	}
+
+	l := len(samples) + repeats*delaySamples
+	wet := make([]float64, l)
+	output := make([]int16, l)
+	for i := 0; i < l; i++ {
+		dry := 0.0
+		if i < len(samples) {
+			dry = float64(samples[i])
+		}
+		if i >= delaySamples {
+			delayedDry := 0.0
+			if i-delaySamples < len(samples) {
+				delayedDry = float64(samples[i-delaySamples])
+			}
+			wet[i] = delayedDry + feedback*wet[i-delaySamples]
+		}
+		output[i] = clampToInt16((1-mix)*dry + mix*wet[i])
+	}
+
+	return output
+}
+
+// combFilter applies a feedback comb filter to the signal
+func combFilter(signal []float64, delaySamples int, feedback float64) []float64 {
+	output := make([]float64, len(signal))
+	for i := range signal {
+		output[i] = signal[i]
+		if i >= delaySamples {
+			output[i] += feedback * output[i-delaySamples]
+		}
+	}
+	return output
+}
+
+// allPass applies a Schroeder allpass filter to the signal, which changes the phase but not the magnitude of each frequency
+func allPass(signal []float64, delaySamples int, gain float64) []float64 {
+	output := make([]float64, len(signal))
+	for i := range signal {
+		delayedInput, delayedOutput := 0.0, 0.0
+		if i >= delaySamples {
+			delayedInput = signal[i-delaySamples]
+			delayedOutput = output[i-delaySamples]
+		}
+		output[i] = -gain*signal[i] + delayedInput + gain*delayedOutput
+	}
+	return output
+}
+
+// AllPassFilter applies a Schroeder allpass filter with the given delay in samples, which keeps the magnitude of
+// every frequency but changes its phase, smearing transients out in time. It is the building block of reverbs and
+// phasers. gain sets how much the signal is dispersed, and is limited to the range -0.99 to 0.99 to keep the filter
+// stable. A delaySamples below 1 is treated as 1. The output has the same length as the input, so any tail is cut off.
+func AllPassFilter(samples []int16, delaySamples int, gain float64) []int16 {
+	delaySamples = max(1, delaySamples)
+	gain = math.Max(-0.99, math.Min(gain, 0.99))
+
+	signal := make([]float64, len(samples))
+	for i, sample := range samples {
+		signal[i] = float64(sample)
+	}
+
+	filteredSamples := make([]int16, len(samples))
+	for i, value := range allPass(signal, delaySamples, gain) {
+		filteredSamples[i] = clampToInt16(value)
+	}
+
+	return filteredSamples
+}
+
+// maxReverbTailSeconds limits the length of the tail that Reverb adds to the samples
+const maxReverbTailSeconds = 10.0
+
+// Reverb adds room ambience to the samples, using a Schroeder reverberator with four parallel comb filters
+// followed by two allpass filters in series. roomSize (0-1) scales the comb filter delays and the decay time,
+// and wetMix (0-1) sets the balance between the dry and the reverberated signal.
+// The output is extended beyond the input so that it includes the reverb tail.
+// If sampleRate is not positive, an unchanged copy is returned.
+func Reverb(samples []int16, sampleRate int, roomSize float64, wetMix float64) []int16 {
+	if sampleRate <= 0 {
+		output := make([]int16, len(samples))
+		copy(output, samples)
+		return output
+	}
+	roomSize = math.Max(0, math.Min(roomSize, 1))
+	wetMix = math.Max(0, math.Min(wetMix, 1))
+
+	combDelaysMs := []float64{29.7, 37.1, 41.1, 43.7}
+	allPassDelaysMs := []float64{5.0, 1.7}
+	scale := 0.5 + roomSize
+	feedback := 0.7 + 0.28*roomSize
+
+	toSamples := func(ms float64) int {
+		n := int(math.Round(ms * float64(sampleRate) / 1000))
+		if n < 1 {
+			n = 1
+		}
+		return n
+	}
+
+	// Make room for the tail, which is the time it takes for the longest comb filter to decay by 60 dB
+	longestComb := toSamples(combDelaysMs[len(combDelaysMs)-1] * scale)
+	tail := int(float64(longestComb) * math.Log(0.001) / math.Log(feedback))
+	if maxTail := int(maxReverbTailSeconds * float64(sampleRate)); tail > maxTail {
+		tail = maxTail
+	}
+
+	l := len(samples) + tail
+	dry := make([]float64, l)
+	for i, sample := range samples {
+		dry[i] = float64(sample)
+	}
+
+	wet := make([]float64, l)
+	for _, delayMs := range combDelaysMs {
+		combed := combFilter(dry, toSamples(delayMs*scale), feedback)
+		for i := range wet {
+			wet[i] += combed[i] / float64(len(combDelaysMs))
+		}
+	}
+	for _, delayMs := range allPassDelaysMs {
+		wet = allPass(wet, toSamples(delayMs), 0.7)
+	}
+
+	output := make([]int16, l)
+	for i := range output {
+		output[i] = clampToInt16((1-wetMix)*dry[i] + wetMix*wet[i])
+	}
+
+	return output
+}
+
+// sampleAt returns the sample at the fractional position, using linear interpolation,
+// or 0 before the start or after the end of the samples, or if the position is NaN or infinite
+func sampleAt(samples []int16, position float64) float64 {
+	if math.IsNaN(position) || position < 0 || position >= float64(len(samples)) {
+		return 0
+	}
+	i := int(position)
+	frac := position - float64(i)
+	next := 0.0
+	if i+1 < len(samples) {
+		next = float64(samples[i+1])
+	}
+	return float64(samples[i])*(1-frac) + next*frac
+}
+
+const (
+	// chorusVoices is the number of modulated copies that Chorus mixes with the dry signal
+	chorusVoices = 3
+
+	// chorusBaseDelayMs is the shortest delay of the chorus voices
+	chorusBaseDelayMs = 15.0
+)
+
+// Chorus thickens the sound by mixing the samples with several delayed copies, where the delay of each copy
+// is modulated by a sine LFO at rateHz. The delays sweep over depthMs milliseconds, with the LFOs of the copies
+// evenly spread in phase, and mix (0-1) sets the balance between the dry and the delayed signal.
+// If sampleRate is not positive, an unchanged copy is returned.
+func Chorus(samples []int16, sampleRate int, rateHz, depthMs, mix float64) []int16 {
+	l := len(samples)
+	output := make([]int16, l)
+	if sampleRate <= 0 {
+		copy(output, samples)
+		return output
+	}
+	mix = math.Max(0, math.Min(mix, 1))
+	depthMs = math.Max(0, depthMs)
+
+	samplesPerMs := float64(sampleRate) / 1000
+	for i := 0; i < l; i++ {
+		t := float64(i) / float64(sampleRate)
+		wet := 0.0
+		for v := 0; v < chorusVoices; v++ {
+			phase := 2 * math.Pi * float64(v) / chorusVoices
+			lfo := 0.5 * (1 + math.Sin(2*math.Pi*rateHz*t+phase))
+			delayMs := chorusBaseDelayMs + depthMs*lfo
+			wet += sampleAt(samples, float64(i)-delayMs*samplesPerMs)
+		}
+		wet /= chorusVoices
+		output[i] = clampToInt16((1-mix)*float64(samples[i]) + mix*wet)
+	}
+
+	return output
+}
+
+// SoftClip saturates the samples with tanh waveshaping, which compresses the peaks smoothly instead of
+// squaring them off. A higher drive gives more saturation. The curve is scaled so that full scale stays
+// at full scale, and small signals are amplified by about drive/tanh(drive). If drive is not positive,
+// an unchanged copy of the samples is returned.
+func SoftClip(samples []int16, drive float64) []int16 {
+	clippedSamples := make([]int16, len(samples))
+	if drive <= 0 {
+		copy(clippedSamples, samples)
+		return clippedSamples
+	}
+
+	scale := math.MaxInt16 / math.Tanh(drive)
+	for i, sample := range samples {
+		x := float64(sample) / math.MaxInt16
+		clippedSamples[i] = clampToInt16(math.Tanh(drive*x) * scale)
+	}
+
+	return clippedSamples
+}
+
+// BitCrush reduces the effective bit depth of the samples by quantizing them to the given number of bits,
+// and reduces the effective sample rate by holding every sampleRateReduction'th sample for that many positions.
+// bits is limited to the range 1 to 16, and a sampleRateReduction below 2 leaves the sample rate unchanged.
+func BitCrush(samples []int16, bits int, sampleRateReduction int) []int16 {
+	if bits < 1 {
+		bits = 1
+	} else if bits > 16 {
+		bits = 16
+	}
+	if sampleRateReduction < 1 {
+		sampleRateReduction = 1
+	}
+
+	shift := uint(16 - bits)
+	crushedSamples := make([]int16, len(samples))
+	var held int16
+	for i, sample := range samples {
+		if i%sampleRateReduction == 0 {
+			// The arithmetic shift rounds towards negative infinity, so there are exactly 2^bits levels
+			held = (sample >> shift) << shift
+		}
+		crushedSamples[i] = held
+	}
+
+	return crushedSamples
+}
+
+// Stutter repeats the first sliceMs milliseconds of the samples, like StutterAt with a start offset of 0
+func Stutter(samples []int16, sampleRate int, sliceMs float64, repeats int) []int16 {
+	return StutterAt(samples, sampleRate, 0, sliceMs, repeats)
+}
+
+// StutterAt creates a stutter effect by playing the sliceMs milliseconds long slice that starts at startMs
+// repeats times in a row, before the rest of the samples are played. The output is longer than the input by
+// repeats-1 slices. The slice is cut off at the end of the samples, and a repeats below 1 is treated as 1.
+func StutterAt(samples []int16, sampleRate int, startMs, sliceMs float64, repeats int) []int16 {
+	if repeats < 1 {
+		repeats = 1
+	}
+
+	start := int(startMs * float64(sampleRate) / 1000)
+	start = max(0, min(start, len(samples)))
+	end := start + int(sliceMs*float64(sampleRate)/1000)
+	end = max(start, min(end, len(samples)))
+
+	slice := samples[start:end]
+	stutteredSamples := make([]int16, 0, len(samples)+(repeats-1)*len(slice))
+	stutteredSamples = append(stutteredSamples, samples[:start]...)
+	for r := 0; r < repeats; r++ {
+		stutteredSamples = append(stutteredSamples, slice...)
+	}
+	stutteredSamples = append(stutteredSamples, samples[end:]...)
+
+	return stutteredSamples
+}
+
+const (
+	// telephoneLowCutoff and telephoneHighCutoff are the edges of the voice band of a classic telephone line
+	telephoneLowCutoff  = 300
+	telephoneHighCutoff = 3400
+)
+
+// TelephoneEffect makes the samples sound like they are played through a phone line, by band-limiting them
+// to the 300 Hz to 3400 Hz voice band. BandPassFilter is applied twice, for steeper 24 dB/octave slopes.
+// If the sample rate is too low for the full band, the high cutoff is moved below the Nyquist frequency,
+// and if there is no room for a band at all, an unchanged copy of the samples is returned.
+func TelephoneEffect(samples []int16, sampleRate int) []int16 {
+	highCutoff := math.Min(telephoneHighCutoff, 0.45*float64(sampleRate))
+	filtered, err := BandPassFilter(samples, sampleRate, telephoneLowCutoff, highCutoff)
+	if err != nil {
+		unchanged := make([]int16, len(samples))
+		copy(unchanged, samples)
+		return unchanged
+	}
+	// The second pass uses the same cutoffs, which were valid for the first pass
+	filtered, _ = BandPassFilter(filtered, sampleRate, telephoneLowCutoff, highCutoff)
+	return filtered
+}
+
+// PitchWobble emulates the wow and flutter of a tape machine, by modulating the playback position with a sine LFO
+// at rateHz. depth is the largest relative change in pitch, so 0.01 makes the pitch wobble by up to 1% around the
+// original pitch. The samples between the modulated positions are found with linear interpolation.
+// The output has the same length as the input. If rateHz or depth is not positive, an unchanged copy is returned.
+func PitchWobble(samples []int16, sampleRate int, rateHz, depth float64) []int16 {
+	output := make([]int16, len(samples))
+	if rateHz <= 0 || depth <= 0 || sampleRate <= 0 {
+		copy(output, samples)
+		return output
+	}
+
+	// The delay is amplitude * (1 - cos(wt)), which starts at 0 and has a slope of at most depth,
+	// so the playback speed varies between 1-depth and 1+depth
+	w := 2 * math.Pi * rateHz / float64(sampleRate)
+	amplitude := depth / w
+	for i := range samples {
+		delay := amplitude * (1 - math.Cos(w*float64(i)))
+		output[i] = clampToInt16(sampleAt(samples, float64(i)-delay))
+	}
+
+	return output
+}
+
+const (
+	// phaserStages is the number of first-order allpass filters in the phaser, which gives phaserStages/2 notches
+	phaserStages = 4
+
+	// phaserMinFrequency and phaserMaxFrequency are the limits of the sweep of the allpass break frequency
+	phaserMinFrequency = 200.0
+	phaserMaxFrequency = 4000.0
+)
+
+// Phaser creates sweeping notches in the spectrum, by mixing the samples with a copy that has been passed through
+// a cascade of first-order allpass filters, where the break frequency is swept by a sine LFO at rateHz.
+// depth (0-1) sets how far the break frequency sweeps from phaserMinFrequency towards phaserMaxFrequency,
+// on a logarithmic scale, and feedback (-0.9 to 0.9) feeds the filtered signal back into the cascade,
+// which makes the notches sharper. The output has the same length as the input.
+func Phaser(samples []int16, sampleRate int, rateHz, depth, feedback float64) []int16 {
+	output := make([]int16, len(samples))
+	if sampleRate <= 0 {
+		copy(output, samples)
+		return output
+	}
+	depth = math.Max(0, math.Min(depth, 1))
+	feedback = math.Max(-0.9, math.Min(feedback, 0.9))
+
+	var previousInputs, previousOutputs [phaserStages]float64
+	wet := 0.0
+	for i, sample := range samples {
+		// The LFO starts at 0, so that the sweep starts at the lowest frequency
+		lfo := 0.5 * (1 - math.Cos(2*math.Pi*rateHz*float64(i)/float64(sampleRate)))
+		frequency := phaserMinFrequency * math.Pow(phaserMaxFrequency/phaserMinFrequency, depth*lfo)
+		t := math.Tan(math.Pi * math.Min(frequency, 0.45*float64(sampleRate)) / float64(sampleRate))
+		coefficient := (t - 1) / (t + 1)
+
+		value := float64(sample) + feedback*wet
+		for s := 0; s < phaserStages; s++ {
+			filtered := coefficient*value + previousInputs[s] - coefficient*previousOutputs[s]
+			previousInputs[s] = value
+			previousOutputs[s] = filtered
+			value = filtered
+		}
+		wet = value
+
+		// Where the cascade shifts the phase by 180 degrees, the dry and the wet signal cancel out
+		output[i] = clampToInt16(0.5 * (float64(sample) + wet))
+	}
+
+	return output
+}
@@ -0,0 +1,399 @@
+package mixorama
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestDelayImpulse(t *testing.T) {
+	impulse := make([]int16, 100)
+	impulse[0] = 10000
+
+	// 10ms at 1000 Hz is a delay of 10 samples
+	delayed := Delay(impulse, 1000, 10, 0.5, 0.5)
+
+	if len(delayed) <= len(impulse) {
+		t.Fatalf("Expected the output to include a tail, got %d samples", len(delayed))
+	}
+
+	expected := map[int]int16{0: 5000, 10: 5000, 20: 2500, 30: 1250, 40: 625}
+	for index, value := range expected {
+		if delayed[index] != value {
+			t.Errorf("Expected %d at index %d, got %d", value, index, delayed[index])
+		}
+	}
+
+	// Everything between the echoes should be silent
+	for i := 1; i < 50; i++ {
+		if i%10 != 0 && delayed[i] != 0 {
+			t.Errorf("Expected silence between echoes at index %d, got %d", i, delayed[i])
+		}
+	}
+}
+
+func TestReverbImpulse(t *testing.T) {
+	sampleRate := 44100
+	impulse := make([]int16, sampleRate/10)
+	impulse[0] = 30000
+
+	reverberated := Reverb(impulse, sampleRate, 0.5, 0.5)
+	if len(reverberated) <= len(impulse) {
+		t.Fatalf("Expected the output to include a reverb tail, got %d samples", len(reverberated))
+	}
+
+	// The tail should be dense, without any silent gaps longer than a millisecond after the early reflections
+	window := reverberated[sampleRate/5 : sampleRate/5+sampleRate/10]
+	longestGap, gap := 0, 0
+	for _, v := range window {
+		if v == 0 {
+			gap++
+			if gap > longestGap {
+				longestGap = gap
+			}
+		} else {
+			gap = 0
+		}
+	}
+	if longestGap > sampleRate/1000 {
+		t.Errorf("Expected a dense reverb tail, but found a silent gap of %d samples", longestGap)
+	}
+
+	// The tail should decay over time
+	early := RMSLevel(reverberated[sampleRate/20 : sampleRate/20+sampleRate/10])
+	late := RMSLevel(reverberated[sampleRate/2 : sampleRate/2+sampleRate/10])
+	if late >= early {
+		t.Errorf("Expected the reverb tail to decay, got early RMS %.2f and late RMS %.2f", early, late)
+	}
+}
+
+// energyOutsideBand returns the fraction of the spectral energy that is more than bandwidth Hz away from frequency
+func energyOutsideBand(samples []int16, sampleRate int, frequency, bandwidth float64) float64 {
+	magnitudes, frequencies := AnalyzeSpectrum(samples, sampleRate)
+	inside, total := 0.0, 0.0
+	for i, magnitude := range magnitudes {
+		energy := magnitude * magnitude
+		total += energy
+		if math.Abs(frequencies[i]-frequency) <= bandwidth {
+			inside += energy
+		}
+	}
+	return 1 - inside/total
+}
+
+func TestChorus(t *testing.T) {
+	sampleRate := 44100
+	samples := createSineWave(440, 10000, sampleRate, sampleRate)
+
+	chorused := Chorus(samples, sampleRate, 1.5, 5, 0.5)
+	if len(chorused) != len(samples) {
+		t.Fatalf("Expected %d samples, got %d", len(samples), len(chorused))
+	}
+
+	differences := 0
+	for i := range samples {
+		if chorused[i] != samples[i] {
+			differences++
+		}
+	}
+	if differences < len(samples)/2 {
+		t.Errorf("Expected most samples to be changed by the chorus, got %d changed samples", differences)
+	}
+
+	// Skip the start, where the delayed copies have not started yet
+	before := energyOutsideBand(samples[sampleRate/10:], sampleRate, 440, 3)
+	after := energyOutsideBand(chorused[sampleRate/10:], sampleRate, 440, 3)
+	if after < 10*before {
+		t.Errorf("Expected the chorus to spread the spectrum, got %.6f of the energy outside of 440 Hz (was %.6f)", after, before)
+	}
+}
+
+func TestReverbInvalidSampleRate(t *testing.T) {
+	samples := createSineWave(440, 10000, 44100, 100)
+	for _, sampleRate := range []int{0, -44100} {
+		if reverberated := Reverb(samples, sampleRate, 0.5, 0.5); !slices.Equal(reverberated, samples) {
+			t.Errorf("Expected an unchanged copy for a sample rate of %d", sampleRate)
+		}
+	}
+}
+
+func TestChorusInvalidSampleRate(t *testing.T) {
+	samples := createSineWave(440, 10000, 44100, 1000)
+	if chorused := Chorus(samples, 0, 1.5, 5, 0.5); !slices.Equal(chorused, samples) {
+		t.Error("Expected an unchanged copy for a sample rate of 0")
+	}
+	if sample := sampleAt(samples, math.Inf(-1)); sample != 0 {
+		t.Errorf("Expected 0 for an infinite position, got %.2f", sample)
+	}
+	if sample := sampleAt(samples, math.NaN()); sample != 0 {
+		t.Errorf("Expected 0 for a NaN position, got %.2f", sample)
+	}
+}
+
+func TestSoftClipSmallSignal(t *testing.T) {
+	drive := 2.0
+	gain := drive / math.Tanh(drive)
+	samples := createSineWave(440, 300, 44100, 1000)
+	for i, v := range SoftClip(samples, drive) {
+		expected := float64(samples[i]) * gain
+		if math.Abs(float64(v)-expected) > 2 {
+			t.Fatalf("Expected small signals to be scaled linearly, expected about %.0f at index %d, got %d", expected, i, v)
+		}
+	}
+}
+
+func TestSoftClipLargeSignal(t *testing.T) {
+	samples := createSineWave(441, 30000, 44100, 1000)
+	soft := SoftClip(samples, 3)
+	hard := ApplyGain(samples, 3)
+
+	// countAtPeak returns how many samples are at the peak amplitude
+	countAtPeak := func(samples []int16) int {
+		peak := FindPeakAmplitude(samples)
+		count := 0
+		for _, v := range samples {
+			if v == peak || v == -peak {
+				count++
+			}
+		}
+		return count
+	}
+
+	if clipped := CountClippedSamples(soft); clipped != 0 {
+		t.Errorf("Expected the soft clipped peaks to stay below full scale, got %d clipped samples", clipped)
+	}
+	if soft, hard := countAtPeak(soft), countAtPeak(hard); soft*10 > hard {
+		t.Errorf("Expected rounded peaks, got %d samples at the peak (hard clipping gives %d)", soft, hard)
+	}
+}
+
+func TestBitCrushQuantization(t *testing.T) {
+	samples := make([]int16, 65536)
+	for i := range samples {
+		samples[i] = int16(i - 32768)
+	}
+
+	crushed := BitCrush(samples, 8, 1)
+	if len(crushed) != len(samples) {
+		t.Fatalf("Expected %d samples, got %d", len(samples), len(crushed))
+	}
+	levels := make(map[int16]bool)
+	for _, v := range crushed {
+		levels[v] = true
+	}
+	if len(levels) != 256 {
+		t.Errorf("Expected 256 distinct levels with 8 bits, got %d", len(levels))
+	}
+}
+
+func TestBitCrushSampleRateReduction(t *testing.T) {
+	samples := []int16{100, 200, 300, 400, 500}
+	crushed := BitCrush(samples, 16, 2)
+	expected := []int16{100, 100, 300, 300, 500}
+	for i, v := range expected {
+		if crushed[i] != v {
+			t.Errorf("Expected %d at index %d, got %d", v, i, crushed[i])
+		}
+	}
+}
+
+func TestStutterAt(t *testing.T) {
+	samples := make([]int16, 100)
+	for i := range samples {
+		samples[i] = int16(i)
+	}
+
+	// At 1000 Hz, a 10ms slice starting at 20ms is samples 20 to 29
+	stuttered := StutterAt(samples, 1000, 20, 10, 3)
+	if len(stuttered) != len(samples)+2*10 {
+		t.Fatalf("Expected %d samples, got %d", len(samples)+2*10, len(stuttered))
+	}
+	for i := 0; i < 20; i++ {
+		if stuttered[i] != int16(i) {
+			t.Fatalf("Expected the samples before the slice to be unchanged, got %d at index %d", stuttered[i], i)
+		}
+	}
+	for r := 0; r < 3; r++ {
+		for j := 0; j < 10; j++ {
+			if v := stuttered[20+r*10+j]; v != int16(20+j) {
+				t.Fatalf("Expected repeat %d of the slice to contain %d at index %d, got %d", r, 20+j, 20+r*10+j, v)
+			}
+		}
+	}
+	for i := 50; i < len(stuttered); i++ {
+		if stuttered[i] != int16(i-20) {
+			t.Fatalf("Expected the samples after the slice to follow the repeats, got %d at index %d", stuttered[i], i)
+		}
+	}
+}
+
+func TestStutter(t *testing.T) {
+	samples := []int16{1, 2, 3, 4, 5}
+	stuttered := Stutter(samples, 1000, 2, 3)
+	expected := []int16{1, 2, 1, 2, 1, 2, 3, 4, 5}
+	if len(stuttered) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, stuttered)
+	}
+	for i, v := range expected {
+		if stuttered[i] != v {
+			t.Fatalf("Expected %v, got %v", expected, stuttered)
+		}
+	}
+
+	// The slice is cut off at the end of the samples
+	if stuttered := StutterAt(samples, 1000, 4, 10, 2); len(stuttered) != 6 || stuttered[5] != 5 {
+		t.Errorf("Expected the last sample to be repeated once, got %v", stuttered)
+	}
+}
+
+func TestTelephoneEffect(t *testing.T) {
+	sampleRate := 44100
+	// peak returns the peak amplitude of a tone after the telephone effect, skipping the start where the filters settle
+	peak := func(frequency float64) int16 {
+		filtered := TelephoneEffect(createSineWave(frequency, 10000, sampleRate, sampleRate/2), sampleRate)
+		return FindPeakAmplitude(filtered[sampleRate/10:])
+	}
+
+	if p := peak(1000); p < 8000 {
+		t.Errorf("Expected a tone in the voice band to pass mostly intact, got peak %d", p)
+	}
+	if p := peak(100); p > 300 {
+		t.Errorf("Expected a tone below 300 Hz to be attenuated, got peak %d", p)
+	}
+	if p := peak(8000); p > 300 {
+		t.Errorf("Expected a tone above 3400 Hz to be attenuated, got peak %d", p)
+	}
+
+	// The voice band is kept for telephone sample rates, too
+	filtered := TelephoneEffect(createSineWave(1000, 10000, 8000, 4000), 8000)
+	if p := FindPeakAmplitude(filtered[800:]); p < 8000 {
+		t.Errorf("Expected a tone in the voice band to pass at 8000 Hz, got peak %d", p)
+	}
+}
+
+func TestPitchWobble(t *testing.T) {
+	sampleRate := 44100
+	samples := createSineWave(1000, 10000, sampleRate, sampleRate)
+
+	wobbled := PitchWobble(samples, sampleRate, 2, 0.02)
+	if len(wobbled) != len(samples) {
+		t.Fatalf("Expected %d samples, got %d", len(samples), len(wobbled))
+	}
+
+	// Find the instantaneous frequency of each cycle from the interpolated upward zero crossings
+	var crossings []float64
+	for i := 1; i < len(wobbled); i++ {
+		if wobbled[i-1] < 0 && wobbled[i] >= 0 {
+			frac := float64(-wobbled[i-1]) / float64(wobbled[i]-wobbled[i-1])
+			crossings = append(crossings, float64(i-1)+frac)
+		}
+	}
+	lowest, highest, sum := math.Inf(1), math.Inf(-1), 0.0
+	for i := 1; i < len(crossings); i++ {
+		frequency := float64(sampleRate) / (crossings[i] - crossings[i-1])
+		lowest = math.Min(lowest, frequency)
+		highest = math.Max(highest, frequency)
+		sum += frequency
+	}
+	mean := sum / float64(len(crossings)-1)
+
+	if lowest > 985 || highest < 1015 {
+		t.Errorf("Expected the frequency to wobble by about 2%% around 1000 Hz, got %.1f Hz to %.1f Hz", lowest, highest)
+	}
+	if lowest < 970 || highest > 1030 {
+		t.Errorf("Expected the frequency to wobble by at most 2%% around 1000 Hz, got %.1f Hz to %.1f Hz", lowest, highest)
+	}
+	if math.Abs(mean-1000) > 5 {
+		t.Errorf("Expected the frequency to wobble around 1000 Hz, got a mean of %.1f Hz", mean)
+	}
+}
+
+func TestAllPassFilterFlatMagnitude(t *testing.T) {
+	sampleRate := 44100
+	for _, frequency := range []float64{200, 1000, 5000} {
+		samples := createSineWave(frequency, 10000, sampleRate, sampleRate/2)
+		filtered := AllPassFilter(samples, 30, 0.7)
+		if len(filtered) != len(samples) {
+			t.Fatalf("Expected %d samples, got %d", len(samples), len(filtered))
+		}
+		// Skip the start, where the filter is settling
+		if peak := FindPeakAmplitude(filtered[sampleRate/10:]); math.Abs(float64(peak)-10000) > 300 {
+			t.Errorf("Expected a %.0f Hz tone to keep its amplitude of 10000, got peak %d", frequency, peak)
+		}
+	}
+}
+
+func TestAllPassFilterDisperses(t *testing.T) {
+	// Noise followed by enough silence for the tail to decay
+	samples := Concat(createNoise(10000, 2000, 6), make([]int16, 4000))
+	filtered := AllPassFilter(samples, 30, 0.7)
+
+	energy := func(samples []int16) float64 {
+		sum := 0.0
+		for _, v := range samples {
+			sum += float64(v) * float64(v)
+		}
+		return sum
+	}
+	if ratio := energy(filtered) / energy(samples); math.Abs(ratio-1) > 0.02 {
+		t.Errorf("Expected the energy to be preserved, got a ratio of %.3f", ratio)
+	}
+
+	// The phase is changed, so the waveform is different
+	correlation := 0.0
+	for i := range samples {
+		correlation += float64(samples[i]) * float64(filtered[i])
+	}
+	if correlation /= energy(samples); correlation > 0.5 {
+		t.Errorf("Expected the waveform to be dispersed, got a normalized correlation of %.2f", correlation)
+	}
+
+	// Energy is moved into the silence after the noise
+	if energy(filtered[2000:]) < energy(samples)/100 {
+		t.Error("Expected the allpass filter to add a tail after the noise")
+	}
+}
+
+func TestPhaserNotchesMove(t *testing.T) {
+	sampleRate := 44100
+	noise := createNoise(10000, sampleRate, 7)
+	phased := Phaser(noise, sampleRate, 1, 1, 0)
+	if len(phased) != len(noise) {
+		t.Fatalf("Expected %d samples, got %d", len(noise), len(phased))
+	}
+
+	// gainAround estimates the gain of the phaser around the given frequency, in a window starting at start,
+	// by comparing the spectra of the input and the output
+	gainAround := func(start int, frequency float64) float64 {
+		inMagnitudes, frequencies := AnalyzeSpectrum(noise[start:start+4096], sampleRate)
+		outMagnitudes, _ := AnalyzeSpectrum(phased[start:start+4096], sampleRate)
+		in, out := 0.0, 0.0
+		for i := range frequencies {
+			if math.Abs(frequencies[i]-frequency) < 30 {
+				in += inMagnitudes[i] * inMagnitudes[i]
+				out += outMagnitudes[i] * outMagnitudes[i]
+			}
+		}
+		return math.Sqrt(out / in)
+	}
+
+	// At the start of the sweep, the break frequency is 200 Hz, which puts a notch at about 480 Hz.
+	// Half a second later, the break frequency is 4000 Hz, which puts a notch at about 1660 Hz.
+	early, late := 1000, sampleRate/2-2048
+	if gain := gainAround(early, 483); gain > 0.3 {
+		t.Errorf("Expected a notch at 483 Hz at the start, got a gain of %.2f", gain)
+	}
+	if gain := gainAround(late, 483); gain < 0.6 {
+		t.Errorf("Expected the notch to move away from 483 Hz, got a gain of %.2f", gain)
+	}
+	if gain := gainAround(late, 1657); gain > 0.3 {
+		t.Errorf("Expected a notch at 1657 Hz after half a second, got a gain of %.2f", gain)
+	}
+	if gain := gainAround(early, 1657); gain < 0.6 {
+		t.Errorf("Expected no notch at 1657 Hz at the start, got a gain of %.2f", gain)
+	}
+
+	if withFeedback := Phaser(noise, sampleRate, 0.5, 0.8, 0.7); len(withFeedback) != len(noise) {
+		t.Errorf("Expected %d samples with feedback, got %d", len(noise), len(withFeedback))
+	}
+}
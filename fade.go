@@ -0,0 +1,133 @@
+package mixorama
+
+import (
+	"errors"
+	"math"
+)
+
+// FadeCurve selects the shape of a fade
+type FadeCurve int
+
+const (
+	// FadeLinear changes the amplitude at a constant rate
+	FadeLinear FadeCurve = iota
+	// FadeExponential changes the amplitude slowly at first and quickly at the end of a fade in
+	FadeExponential
+	// FadeLogarithmic changes the amplitude quickly at first and slowly at the end of a fade in
+	FadeLogarithmic
+)
+
+// gain returns the amplitude factor for a position from 0 (silent) to 1 (full amplitude)
+func (curve FadeCurve) gain(x float64) float64 {
+	switch curve {
+	case FadeExponential:
+		return (math.Pow(10, x) - 1) / 9
+	case FadeLogarithmic:
+		return math.Log10(1 + 9*x)
+	default:
+		return x
+	}
+}
+
+// fadePosition returns the position from 0 to 1 of sample i in a fade that is n samples long
+func fadePosition(i, n int) float64 {
+	if n <= 1 {
+		return 0
+	}
+	return float64(i) / float64(n-1)
+}
+
+// FadeIn scales the first durationSamples samples from silence up to full amplitude, using the given curve.
+// The rest of the samples are left untouched. If durationSamples is longer than the samples, the entire slice is faded.
+func FadeIn(samples []int16, durationSamples int, curve FadeCurve) []int16 {
+	l := len(samples)
+	n := durationSamples
+	if n > l {
+		n = l
+	}
+
+	fadedSamples := make([]int16, l)
+	copy(fadedSamples, samples)
+
+	for i := 0; i < n; i++ {
+		fadedSamples[i] = int16(math.Round(float64(samples[i]) * curve.gain(fadePosition(i, n))))
+	}
+
+	return fadedSamples
+}
+
+// FadeOut scales the last durationSamples samples from full amplitude down to silence, using the given curve.
+// The rest of the samples are left untouched. If durationSamples is longer than the samples, the entire slice is faded.
+func FadeOut(samples []int16, durationSamples int, curve FadeCurve) []int16 {
+	l := len(samples)
+	n := durationSamples
+	if n > l {
+		n = l
+	}
+
+	fadedSamples := make([]int16, l)
+	copy(fadedSamples, samples)
+
+	start := l - n
+	for i := 0; i < n; i++ {
+		fadedSamples[start+i] = int16(math.Round(float64(samples[start+i]) * curve.gain(1-fadePosition(i, n))))
+	}
+
+	return fadedSamples
+}
+
+// Crossfade joins two clips end-to-end, fading out the tail of a while fading in the head of b over overlapSamples samples.
+// The fades use equal-power gains, so that the perceived loudness stays constant through the overlap.
+// The result has len(a)+len(b)-overlapSamples samples.
+func Crossfade(a, b []int16, overlapSamples int) ([]int16, error) {
+	if overlapSamples < 0 {
+		return nil, errors.New("overlap can not be negative")
+	}
+	if overlapSamples > len(a) || overlapSamples > len(b) {
+		return nil, errors.New("overlap is longer than one of the inputs")
+	}
+
+	start := len(a) - overlapSamples
+	combined := make([]int16, len(a)+len(b)-overlapSamples)
+	copy(combined, a[:start])
+
+	for i := 0; i < overlapSamples; i++ {
+		angle := fadePosition(i, overlapSamples) * math.Pi / 2
+		mixed := float64(a[start+i])*math.Cos(angle) + float64(b[i])*math.Sin(angle)
+		combined[start+i] = clampToInt16(mixed)
+	}
+
+	copy(combined[len(a):], b[overlapSamples:])
+
+	return combined, nil
+}
+
+// MakeLoopable crossfades the tail of the samples into the head over crossfadeSamples samples, so that the result
+// can be looped without a click at the seam. The result has len(samples)-crossfadeSamples samples.
+// crossfadeSamples is limited to half the length of the samples, and if it is not positive,
+// an unchanged copy of the samples is returned.
+func MakeLoopable(samples []int16, crossfadeSamples int) []int16 {
+	l := len(samples)
+	if crossfadeSamples > l/2 {
+		crossfadeSamples = l / 2
+	}
+	if crossfadeSamples <= 0 {
+		looped := make([]int16, l)
+		copy(looped, samples)
+		return looped
+	}
+
+	tailStart := l - crossfadeSamples
+	looped := make([]int16, tailStart)
+
+	// The loop starts where the tail left off, and fades into the head of the samples
+	for i := 0; i < crossfadeSamples; i++ {
+		angle := fadePosition(i, crossfadeSamples) * math.Pi / 2
+		mixed := float64(samples[tailStart+i])*math.Cos(angle) + float64(samples[i])*math.Sin(angle)
+		looped[i] = clampToInt16(mixed)
+	}
+
+	copy(looped[crossfadeSamples:], samples[crossfadeSamples:tailStart])
+
+	return looped
+}
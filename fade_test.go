@@ -0,0 +1,130 @@
+package mixorama
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFadeIn(t *testing.T) {
+	samples := createTestWaveform(10000, 21)
+
+	expectedMidpoints := map[FadeCurve]float64{
+		FadeLinear:      0.5,
+		FadeExponential: (math.Sqrt(10) - 1) / 9,
+		FadeLogarithmic: math.Log10(5.5),
+	}
+
+	for curve, expectedGain := range expectedMidpoints {
+		faded := FadeIn(samples, 11, curve)
+		if len(faded) != len(samples) {
+			t.Fatalf("Expected %d samples, got %d", len(samples), len(faded))
+		}
+		if faded[0] != 0 {
+			t.Errorf("Expected the first sample to be silent for curve %d, got %d", curve, faded[0])
+		}
+		if faded[10] != 10000 {
+			t.Errorf("Expected the last faded sample to be at full amplitude for curve %d, got %d", curve, faded[10])
+		}
+		expected := int16(math.Round(10000 * expectedGain))
+		if faded[5] != expected {
+			t.Errorf("Expected midpoint %d for curve %d, got %d", expected, curve, faded[5])
+		}
+		// The samples after the fade should be untouched
+		for i := 11; i < len(faded); i++ {
+			if faded[i] != samples[i] {
+				t.Errorf("Expected sample %d to be untouched, got %d", i, faded[i])
+			}
+		}
+	}
+}
+
+func TestFadeOut(t *testing.T) {
+	samples := createTestWaveform(10000, 21)
+	faded := FadeOut(samples, 11, FadeLinear)
+
+	if faded[len(faded)-1] != 0 {
+		t.Errorf("Expected the last sample to be silent, got %d", faded[len(faded)-1])
+	}
+	if faded[15] != 5000 {
+		t.Errorf("Expected midpoint 5000, got %d", faded[15])
+	}
+	for i := 0; i < 10; i++ {
+		if faded[i] != samples[i] {
+			t.Errorf("Expected sample %d to be untouched, got %d", i, faded[i])
+		}
+	}
+}
+
+func TestFadeClamping(t *testing.T) {
+	samples := createTestWaveform(10000, 5)
+
+	fadedIn := FadeIn(samples, 100, FadeLinear)
+	if len(fadedIn) != len(samples) || fadedIn[0] != 0 || fadedIn[4] != 10000 {
+		t.Errorf("Expected the fade in to cover the whole slice, got %v", fadedIn)
+	}
+
+	fadedOut := FadeOut(samples, 100, FadeLinear)
+	if len(fadedOut) != len(samples) || fadedOut[0] != 10000 || fadedOut[4] != 0 {
+		t.Errorf("Expected the fade out to cover the whole slice, got %v", fadedOut)
+	}
+}
+
+func TestCrossfade(t *testing.T) {
+	a := createTestWaveform(10000, 100)
+	b := createTestWaveform(10000, 50)
+
+	combined, err := Crossfade(a, b, 20)
+	if err != nil {
+		t.Fatalf("Error in Crossfade: %v", err)
+	}
+	if len(combined) != 130 {
+		t.Fatalf("Expected %d samples, got %d", 130, len(combined))
+	}
+
+	// With equal-power gains, the overlap stays at roughly the constant level, with at most a 3 dB bump in the middle
+	for i := 80; i < 100; i++ {
+		if combined[i] < 9900 || combined[i] > 14150 {
+			t.Errorf("Expected sample %d in the overlap to be close to the constant level, got %d", i, combined[i])
+		}
+	}
+	if combined[0] != 10000 || combined[129] != 10000 {
+		t.Errorf("Expected the samples outside the overlap to be untouched, got %d and %d", combined[0], combined[129])
+	}
+}
+
+func TestCrossfadeErrors(t *testing.T) {
+	a := createTestWaveform(1000, 10)
+	b := createTestWaveform(1000, 5)
+	if _, err := Crossfade(a, b, 6); err == nil {
+		t.Error("Expected error for an overlap longer than one of the inputs")
+	}
+	if _, err := Crossfade(a, b, -1); err == nil {
+		t.Error("Expected error for a negative overlap")
+	}
+}
+
+func TestMakeLoopable(t *testing.T) {
+	// 10.25 periods of a sine wave, so that looping it as it is gives a large jump at the seam
+	samples := createSineWave(441, 10000, 44100, 1025)
+
+	// maxJump returns the largest difference between two neighbouring samples
+	maxJump := func(samples []int16) float64 {
+		jump := 0.0
+		for i := 1; i < len(samples); i++ {
+			jump = math.Max(jump, math.Abs(float64(samples[i])-float64(samples[i-1])))
+		}
+		return jump
+	}
+	smooth := maxJump(samples)
+	if maxJump(Concat(samples, samples)) < 2*smooth {
+		t.Fatal("Expected the unprocessed loop to have a large jump at the seam")
+	}
+
+	looped := MakeLoopable(samples, 200)
+	if len(looped) != len(samples)-200 {
+		t.Fatalf("Expected %d samples, got %d", len(samples)-200, len(looped))
+	}
+	if jump := maxJump(Concat(looped, looped)); jump > 1.5*smooth {
+		t.Errorf("Expected no large jumps when looping, got a jump of %.0f (the sine wave has %.0f)", jump, smooth)
+	}
+}
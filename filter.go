@@ -0,0 +1,272 @@
+package mixorama
+
+import (
+	"errors"
+	"math"
+)
+
+// LowPassFilterN is a low-pass filter that cascades the single-pole filter of LowPassFilter order times.
+// Each stage adds 6 dB/octave of attenuation above the cutoff frequency, so order 4 gives 24 dB/octave.
+// An order of 1 or below gives exactly the same result as LowPassFilter. Higher orders keep the state of each
+// stage as a float64 and round the output, instead of truncating to int16 after every sample.
+func LowPassFilterN(samples []int16, sampleRate int, cutoffFrequency float64, order int) []int16 {
+	if order <= 1 {
+		return LowPassFilter(samples, sampleRate, cutoffFrequency)
+	}
+
+	rc := 1.0 / (2.0 * math.Pi * cutoffFrequency)
+	dt := 1.0 / float64(sampleRate)
+	alpha := dt / (rc + dt)
+
+	filteredSamples := make([]int16, len(samples))
+	if len(samples) == 0 {
+		return filteredSamples
+	}
+
+	// Keep the state of each stage as a float64, so that rounding errors do not accumulate between the stages
+	stages := make([]float64, order)
+	for s := range stages {
+		stages[s] = float64(samples[0])
+	}
+	filteredSamples[0] = samples[0]
+
+	for i := 1; i < len(samples); i++ {
+		input := float64(samples[i])
+		for s := range stages {
+			stages[s] += alpha * (input - stages[s])
+			input = stages[s]
+		}
+		filteredSamples[i] = clampToInt16(input)
+	}
+
+	return filteredSamples
+}
+
+// biquad is a second-order IIR filter section, with coefficients normalized so that a0 is 1
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	x1, x2, y1, y2     float64
+}
+
+// process filters one sample, using the Direct Form I structure
+func (f *biquad) process(x float64) float64 {
+	y := f.b0*x + f.b1*f.x1 + f.b2*f.x2 - f.a1*f.y1 - f.a2*f.y2
+	f.x2, f.x1 = f.x1, x
+	f.y2, f.y1 = f.y1, y
+	return y
+}
+
+// butterworthQ is the Q factor of a second-order Butterworth filter, which has a maximally flat passband
+const butterworthQ = 1 / math.Sqrt2
+
+// lowPassBiquad returns a second-order low-pass filter, using the formulas from the RBJ Audio EQ Cookbook
+func lowPassBiquad(sampleRate int, cutoffFrequency, q float64) *biquad {
+	w0 := 2 * math.Pi * cutoffFrequency / float64(sampleRate)
+	alpha := math.Sin(w0) / (2 * q)
+	cosW0 := math.Cos(w0)
+	a0 := 1 + alpha
+	return &biquad{
+		b0: (1 - cosW0) / 2 / a0,
+		b1: (1 - cosW0) / a0,
+		b2: (1 - cosW0) / 2 / a0,
+		a1: -2 * cosW0 / a0,
+		a2: (1 - alpha) / a0,
+	}
+}
+
+// highPassBiquad returns a second-order high-pass filter, using the formulas from the RBJ Audio EQ Cookbook
+func highPassBiquad(sampleRate int, cutoffFrequency, q float64) *biquad {
+	w0 := 2 * math.Pi * cutoffFrequency / float64(sampleRate)
+	alpha := math.Sin(w0) / (2 * q)
+	cosW0 := math.Cos(w0)
+	a0 := 1 + alpha
+	return &biquad{
+		b0: (1 + cosW0) / 2 / a0,
+		b1: -(1 + cosW0) / a0,
+		b2: (1 + cosW0) / 2 / a0,
+		a1: -2 * cosW0 / a0,
+		a2: (1 - alpha) / a0,
+	}
+}
+
+// peakingBiquad returns a second-order peaking EQ filter, using the formulas from the RBJ Audio EQ Cookbook
+func peakingBiquad(sampleRate int, centerFrequency, q, gainDB float64) *biquad {
+	a := math.Pow(10, gainDB/40)
+	w0 := 2 * math.Pi * centerFrequency / float64(sampleRate)
+	alpha := math.Sin(w0) / (2 * q)
+	cosW0 := math.Cos(w0)
+	a0 := 1 + alpha/a
+	return &biquad{
+		b0: (1 + alpha*a) / a0,
+		b1: -2 * cosW0 / a0,
+		b2: (1 - alpha*a) / a0,
+		a1: -2 * cosW0 / a0,
+		a2: (1 - alpha/a) / a0,
+	}
+}
+
+// applyBiquads runs the samples through the given filters in series
+func applyBiquads(samples []int16, filters ...*biquad) []int16 {
+	filteredSamples := make([]int16, len(samples))
+	for i, sample := range samples {
+		value := float64(sample)
+		for _, filter := range filters {
+			value = filter.process(value)
+		}
+		filteredSamples[i] = clampToInt16(value)
+	}
+	return filteredSamples
+}
+
+// BandPassFilter keeps the frequencies between lowCutoff and highCutoff, by applying a second-order
+// Butterworth high-pass filter at lowCutoff followed by a second-order Butterworth low-pass filter at highCutoff.
+func BandPassFilter(samples []int16, sampleRate int, lowCutoff, highCutoff float64) ([]int16, error) {
+	if lowCutoff <= 0 {
+		return nil, errors.New("low cutoff frequency must be positive")
+	}
+	if lowCutoff >= highCutoff {
+		return nil, errors.New("low cutoff frequency must be lower than the high cutoff frequency")
+	}
+	if highCutoff >= float64(sampleRate)/2 {
+		return nil, errors.New("high cutoff frequency must be below half the sample rate")
+	}
+
+	highPass := highPassBiquad(sampleRate, lowCutoff, butterworthQ)
+	lowPass := lowPassBiquad(sampleRate, highCutoff, butterworthQ)
+	return applyBiquads(samples, highPass, lowPass), nil
+}
+
+// EQBand boosts (gainDB > 0) or cuts (gainDB < 0) the frequencies around centerFrequency, using a peaking EQ
+// biquad filter. A higher q gives a narrower band. The result is clamped to the int16 range.
+func EQBand(samples []int16, sampleRate int, centerFrequency, q, gainDB float64) []int16 {
+	return applyBiquads(samples, peakingBiquad(sampleRate, centerFrequency, q, gainDB))
+}
+
+// AutoEQFrequencies are the center frequencies of the octave bands that AutoEQ measures and corrects
+var AutoEQFrequencies = []float64{31.25, 62.5, 125, 250, 500, 1000, 2000, 4000, 8000, 16000}
+
+const (
+	// maxAutoEQGainDB is the largest boost or cut that AutoEQ applies to a single band
+	maxAutoEQGainDB = 12
+	// autoEQRangeDB is how far below the strongest band a band can be before AutoEQ leaves it alone,
+	// so that bands without any content are not boosted
+	autoEQRangeDB = 60
+	// autoEQQ gives each peaking filter a bandwidth of about one octave
+	autoEQQ = math.Sqrt2
+)
+
+// AutoEQ measures the average spectrum level in each of the octave bands in AutoEQFrequencies and applies
+// a peaking EQ filter to each band, to push the spectrum towards targetCurve. targetCurve has one level in dB
+// per band, relative to the other bands, and missing values are treated as 0 dB, so an empty curve gives
+// a flat spectrum, like that of white noise. Only the shape of the spectrum is changed, not the overall level.
+// Each band is boosted or cut by at most maxAutoEQGainDB, and bands above the Nyquist frequency or
+// without any content are left alone.
+func AutoEQ(samples []int16, sampleRate int, targetCurve []float64) []int16 {
+	magnitudes, frequencies := AnalyzeSpectrum(samples, sampleRate)
+
+	// Measure the level of each band, in dB
+	levels := make([]float64, len(AutoEQFrequencies))
+	strongest := math.Inf(-1)
+	for b, center := range AutoEQFrequencies {
+		low, high := center/math.Sqrt2, center*math.Sqrt2
+		if high > float64(sampleRate)/2 {
+			levels[b] = math.Inf(-1)
+			continue
+		}
+		energy, bins := 0.0, 0
+		for i, magnitude := range magnitudes {
+			if frequencies[i] >= low && frequencies[i] < high {
+				energy += magnitude * magnitude
+				bins++
+			}
+		}
+		if bins == 0 {
+			levels[b] = math.Inf(-1)
+			continue
+		}
+		// Silent bands get a level of -Inf
+		levels[b] = 10 * math.Log10(energy/float64(bins))
+		strongest = math.Max(strongest, levels[b])
+	}
+
+	// Find how far each band is from the target, relative to the average difference
+	differences := make([]float64, len(levels))
+	used := make([]bool, len(levels))
+	sum, count := 0.0, 0
+	for b, level := range levels {
+		if math.IsInf(level, -1) || level < strongest-autoEQRangeDB {
+			continue
+		}
+		target := 0.0
+		if b < len(targetCurve) {
+			target = targetCurve[b]
+		}
+		differences[b] = level - target
+		used[b] = true
+		sum += differences[b]
+		count++
+	}
+	if count == 0 {
+		output := make([]int16, len(samples))
+		copy(output, samples)
+		return output
+	}
+	average := sum / float64(count)
+
+	var filters []*biquad
+	for b, center := range AutoEQFrequencies {
+		if !used[b] {
+			continue
+		}
+		gainDB := math.Max(-maxAutoEQGainDB, math.Min(maxAutoEQGainDB, average-differences[b]))
+		filters = append(filters, peakingBiquad(sampleRate, center, autoEQQ, gainDB))
+	}
+	return applyBiquads(samples, filters...)
+}
+
+// FIRFilter convolves the samples with the FIR filter kernel in coefficients, where coefficients[k] is the weight
+// of the sample k steps back. Samples before the start are treated as zeros. The output has the same length as
+// the input, and is clamped to the int16 range. Without any coefficients, the output is silent.
+func FIRFilter(samples []int16, coefficients []float64) []int16 {
+	filteredSamples := make([]int16, len(samples))
+	for i := range samples {
+		sum := 0.0
+		for k, coefficient := range coefficients {
+			if k > i {
+				break
+			}
+			sum += coefficient * float64(samples[i-k])
+		}
+		filteredSamples[i] = clampToInt16(sum)
+	}
+	return filteredSamples
+}
+
+// DesignLowPassFIR returns the coefficients of a windowed-sinc low-pass FIR filter with numTaps taps, for use with
+// FIRFilter. The sinc is windowed with a Hamming window, and the coefficients are normalized to sum to 1, so that
+// DC passes unchanged. The coefficients are symmetric, which gives a linear phase response with a delay of
+// (numTaps-1)/2 samples. More taps give a steeper transition from the passband to the stopband.
+// If numTaps is below 1, or the cutoff frequency is not between 0 and half the sample rate, nil is returned.
+func DesignLowPassFIR(cutoff float64, sampleRate int, numTaps int) []float64 {
+	if numTaps < 1 || cutoff <= 0 || cutoff >= float64(sampleRate)/2 {
+		return nil
+	}
+
+	normalizedCutoff := cutoff / float64(sampleRate)
+	middle := float64(numTaps-1) / 2
+	coefficients := make([]float64, numTaps)
+	sum := 0.0
+	for n := range coefficients {
+		window := 1.0
+		if numTaps > 1 {
+			window = 0.54 - 0.46*math.Cos(2*math.Pi*float64(n)/float64(numTaps-1))
+		}
+		coefficients[n] = 2 * normalizedCutoff * sinc(2*normalizedCutoff*(float64(n)-middle)) * window
+		sum += coefficients[n]
+	}
+	for n := range coefficients {
+		coefficients[n] /= sum
+	}
+
+	return coefficients
+}
@@ -0,0 +1,214 @@
+package mixorama
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestLowPassFilterN(t *testing.T) {
+	samples := createSineWave(10000, 10000, 44100, 4410)
+
+	order1 := LowPassFilterN(samples, 44100, 1000, 1)
+	order4 := LowPassFilterN(samples, 44100, 1000, 4)
+
+	if len(order1) != len(samples) || len(order4) != len(samples) {
+		t.Fatalf("Expected filtered samples to have the same length as the input")
+	}
+
+	// Skip the start, where the filters are settling
+	peak1 := FindPeakAmplitude(order1[1000:])
+	peak4 := FindPeakAmplitude(order4[1000:])
+	if peak4 >= peak1 {
+		t.Errorf("Expected order 4 to attenuate more than order 1, got peaks %d and %d", peak4, peak1)
+	}
+	if peak4 > 10 {
+		t.Errorf("Expected order 4 to almost remove a 10kHz tone with a 1kHz cutoff, got peak %d", peak4)
+	}
+}
+
+func TestLowPassFilterNDC(t *testing.T) {
+	// A DC signal should pass through unchanged
+	samples := createTestWaveform(5000, 100)
+	for i, v := range LowPassFilterN(samples, 44100, 1000, 4) {
+		if v != 5000 {
+			t.Fatalf("Expected DC to pass unchanged, got %d at index %d", v, i)
+		}
+	}
+}
+
+func TestLowPassFilterNOrder1(t *testing.T) {
+	samples := createNoise(10000, 4410, 1)
+	expected := LowPassFilter(samples, 44100, 1000)
+	for _, order := range []int{1, 0} {
+		if filtered := LowPassFilterN(samples, 44100, 1000, order); !slices.Equal(filtered, expected) {
+			t.Errorf("Expected order %d to give the same result as LowPassFilter", order)
+		}
+	}
+}
+
+func TestBandPassFilter(t *testing.T) {
+	sampleRate := 44100
+	inBand := createSineWave(1000, 10000, sampleRate, sampleRate/2)
+	below := createSineWave(50, 10000, sampleRate, sampleRate/2)
+	above := createSineWave(15000, 10000, sampleRate, sampleRate/2)
+
+	peak := func(samples []int16) int16 {
+		filtered, err := BandPassFilter(samples, sampleRate, 500, 2000)
+		if err != nil {
+			t.Fatalf("Error in BandPassFilter: %v", err)
+		}
+		if len(filtered) != len(samples) {
+			t.Fatalf("Expected %d samples, got %d", len(samples), len(filtered))
+		}
+		// Skip the start, where the filter is settling
+		return FindPeakAmplitude(filtered[sampleRate/10:])
+	}
+
+	if p := peak(inBand); p < 8000 {
+		t.Errorf("Expected a tone inside the band to pass mostly intact, got peak %d", p)
+	}
+	if p := peak(below); p > 500 {
+		t.Errorf("Expected a tone below the band to be attenuated, got peak %d", p)
+	}
+	if p := peak(above); p > 500 {
+		t.Errorf("Expected a tone above the band to be attenuated, got peak %d", p)
+	}
+}
+
+func TestBandPassFilterErrors(t *testing.T) {
+	samples := createTestWaveform(1000, 10)
+	if _, err := BandPassFilter(samples, 44100, 2000, 500); err == nil {
+		t.Error("Expected error when the low cutoff is above the high cutoff")
+	}
+	if _, err := BandPassFilter(samples, 44100, 1000, 1000); err == nil {
+		t.Error("Expected error when the low cutoff equals the high cutoff")
+	}
+}
+
+func TestEQBand(t *testing.T) {
+	sampleRate := 44100
+	center := createSineWave(1000, 5000, sampleRate, sampleRate/2)
+	distant := createSineWave(10000, 5000, sampleRate, sampleRate/2)
+
+	// Skip the start, where the filter is settling
+	boostedCenter := FindPeakAmplitude(EQBand(center, sampleRate, 1000, 1, 6)[sampleRate/10:])
+	boostedDistant := FindPeakAmplitude(EQBand(distant, sampleRate, 1000, 1, 6)[sampleRate/10:])
+
+	// A 6 dB boost doubles the amplitude
+	if boostedCenter < 9500 || boostedCenter > 10500 {
+		t.Errorf("Expected a tone at the center frequency to be boosted to about 10000, got peak %d", boostedCenter)
+	}
+	if boostedDistant < 4800 || boostedDistant > 5300 {
+		t.Errorf("Expected a distant tone to be about unchanged at 5000, got peak %d", boostedDistant)
+	}
+
+	cutCenter := FindPeakAmplitude(EQBand(center, sampleRate, 1000, 1, -6)[sampleRate/10:])
+	if cutCenter < 2400 || cutCenter > 2600 {
+		t.Errorf("Expected a tone at the center frequency to be cut to about 2500, got peak %d", cutCenter)
+	}
+}
+
+func TestAutoEQ(t *testing.T) {
+	sampleRate := 44100
+	// Broadband noise with a strong bass tone
+	noise := createNoise(2000, sampleRate, 5)
+	bass := createSineWave(100, 15000, sampleRate, sampleRate)
+	samples, err := LinearSummation(noise, bass)
+	if err != nil {
+		t.Fatalf("Error in LinearSummation: %v", err)
+	}
+
+	// bandEnergy returns the spectral energy between low and high Hz
+	bandEnergy := func(samples []int16, low, high float64) float64 {
+		magnitudes, frequencies := AnalyzeSpectrum(samples, sampleRate)
+		energy := 0.0
+		for i, magnitude := range magnitudes {
+			if frequencies[i] >= low && frequencies[i] < high {
+				energy += magnitude * magnitude
+			}
+		}
+		return energy
+	}
+
+	equalized := AutoEQ(samples, sampleRate, nil)
+	if len(equalized) != len(samples) {
+		t.Fatalf("Expected %d samples, got %d", len(samples), len(equalized))
+	}
+
+	before := bandEnergy(samples, 50, 200) / bandEnergy(samples, 2000, 8000)
+	after := bandEnergy(equalized, 50, 200) / bandEnergy(equalized, 2000, 8000)
+	if after*4 > before {
+		t.Errorf("Expected the low frequencies to be reduced relative to the highs, got a ratio of %.2f (was %.2f)", after, before)
+	}
+
+	// Silence is left alone
+	for i, v := range AutoEQ(make([]int16, 1000), sampleRate, nil) {
+		if v != 0 {
+			t.Fatalf("Expected silence to stay silent, got %d at index %d", v, i)
+		}
+	}
+}
+
+func TestFIRFilterMovingAverage(t *testing.T) {
+	samples := []int16{300, 600, 900, 1200, 1500, 0, 0}
+	average := []float64{1.0 / 3, 1.0 / 3, 1.0 / 3}
+
+	filtered := FIRFilter(samples, average)
+	// The first two outputs include zeros from before the start
+	expected := []int16{100, 300, 600, 900, 1200, 900, 500}
+	if len(filtered) != len(expected) {
+		t.Fatalf("Expected %d samples, got %d", len(expected), len(filtered))
+	}
+	for i, v := range expected {
+		if filtered[i] != v {
+			t.Errorf("Expected %d at index %d, got %d", v, i, filtered[i])
+		}
+	}
+
+	// A single coefficient of 1 leaves the samples unchanged, and a delayed one delays them
+	delayed := FIRFilter(samples, []float64{0, 1})
+	for i := 1; i < len(samples); i++ {
+		if delayed[i] != samples[i-1] {
+			t.Errorf("Expected the samples to be delayed by one step, got %d at index %d", delayed[i], i)
+		}
+	}
+}
+
+func TestDesignLowPassFIR(t *testing.T) {
+	sampleRate := 44100
+	coefficients := DesignLowPassFIR(2000, sampleRate, 101)
+	if len(coefficients) != 101 {
+		t.Fatalf("Expected 101 coefficients, got %d", len(coefficients))
+	}
+
+	sum := 0.0
+	for _, c := range coefficients {
+		sum += c
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("Expected the coefficients to sum to 1, got %f", sum)
+	}
+	for i := range coefficients {
+		if math.Abs(coefficients[i]-coefficients[len(coefficients)-1-i]) > 1e-12 {
+			t.Fatalf("Expected symmetric coefficients, got %f and %f at %d", coefficients[i], coefficients[len(coefficients)-1-i], i)
+		}
+	}
+
+	// The filter passes a tone below the cutoff and removes a tone far above it
+	low := FIRFilter(createSineWave(500, 10000, sampleRate, 4000), coefficients)
+	high := FIRFilter(createSineWave(8000, 10000, sampleRate, 4000), coefficients)
+	if peak := FindPeakAmplitude(low[200:]); peak < 9500 {
+		t.Errorf("Expected a tone below the cutoff to pass, got peak %d", peak)
+	}
+	if peak := FindPeakAmplitude(high[200:]); peak > 100 {
+		t.Errorf("Expected a tone above the cutoff to be removed, got peak %d", peak)
+	}
+
+	if DesignLowPassFIR(30000, sampleRate, 101) != nil {
+		t.Error("Expected nil for a cutoff above the Nyquist frequency")
+	}
+	if DesignLowPassFIR(2000, sampleRate, 0) != nil {
+		t.Error("Expected nil for no taps")
+	}
+}
@@ -0,0 +1,131 @@
+package mixorama
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/mewkiz/flac"
+	"github.com/mewkiz/flac/frame"
+	"github.com/mewkiz/flac/meta"
+)
+
+// flacBlockSize is the number of samples per channel in each frame written by SaveFlac
+const flacBlockSize = 4096
+
+// LoadFlac loads a .flac file and returns its samples as []int16 (stereo) along with the sample rate.
+// If the file is mono, it converts it to stereo by duplicating the mono channel to both the left and right channels.
+// Samples with a bit depth other than 16 are scaled to 16 bits.
+func LoadFlac(filename string) ([]int16, int, error) {
+	stream, err := flac.Open(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer stream.Close()
+
+	numChannels := int(stream.Info.NChannels)
+	bitDepth := int(stream.Info.BitsPerSample)
+
+	// toInt16 scales a sample from the bit depth of the file to 16 bits
+	toInt16 := func(sample int32) int16 {
+		if bitDepth > 16 {
+			return int16(sample >> (bitDepth - 16))
+		}
+		return int16(sample << (16 - bitDepth))
+	}
+
+	outChannels := numChannels
+	if numChannels == 1 {
+		outChannels = 2
+	}
+
+	samples := make([]int16, 0, int(stream.Info.NSamples)*outChannels)
+	for {
+		f, err := stream.ParseNext()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		for i := 0; i < int(f.BlockSize); i++ {
+			if numChannels == 1 {
+				// Convert mono to stereo by duplicating the mono channel
+				monoSample := toInt16(f.Subframes[0].Samples[i])
+				samples = append(samples, monoSample, monoSample)
+				continue
+			}
+			for _, subframe := range f.Subframes {
+				samples = append(samples, toInt16(subframe.Samples[i]))
+			}
+		}
+	}
+
+	return samples, int(stream.Info.SampleRate), nil
+}
+
+// SaveFlac saves a slice of interleaved int16 samples with the given number of channels as a 16-bit .flac file
+func SaveFlac(filename string, samples []int16, sampleRate, numChannels int) error {
+	if numChannels < 1 || numChannels > 8 {
+		return errors.New("flac supports between 1 and 8 channels")
+	}
+	if len(samples)%numChannels != 0 {
+		return errors.New("the number of samples is not a multiple of the number of channels")
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	numFrames := len(samples) / numChannels
+	info := &meta.StreamInfo{
+		BlockSizeMin:  16,
+		BlockSizeMax:  flacBlockSize,
+		SampleRate:    uint32(sampleRate),
+		NChannels:     uint8(numChannels),
+		BitsPerSample: 16,
+		NSamples:      uint64(numFrames),
+	}
+	encoder, err := flac.NewEncoder(f, info)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < numFrames; start += flacBlockSize {
+		blockSize := numFrames - start
+		if blockSize > flacBlockSize {
+			blockSize = flacBlockSize
+		}
+
+		subframes := make([]*frame.Subframe, numChannels)
+		for c := 0; c < numChannels; c++ {
+			channelSamples := make([]int32, blockSize)
+			for i := 0; i < blockSize; i++ {
+				channelSamples[i] = int32(samples[(start+i)*numChannels+c])
+			}
+			subframes[c] = &frame.Subframe{
+				SubHeader: frame.SubHeader{Pred: frame.PredVerbatim},
+				Samples:   channelSamples,
+				NSamples:  blockSize,
+			}
+		}
+
+		blockFrame := &frame.Frame{
+			Header: frame.Header{
+				HasFixedBlockSize: true,
+				BlockSize:         uint16(blockSize),
+				SampleRate:        uint32(sampleRate),
+				Channels:          frame.Channels(numChannels - 1),
+				BitsPerSample:     16,
+			},
+			Subframes: subframes,
+		}
+		if err := encoder.WriteFrame(blockFrame); err != nil {
+			return err
+		}
+	}
+
+	return encoder.Close()
+}
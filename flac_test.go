@@ -0,0 +1,65 @@
+package mixorama
+
+import (
+	"math"
+	"os"
+	"testing"
+)
+
+func TestFlacRoundTrip(t *testing.T) {
+	// Create a stereo buffer that is longer than one flac block, with different content in each channel
+	samples := make([]int16, 2*10000)
+	for i := 0; i < len(samples)/2; i++ {
+		samples[2*i] = int16(20000 * math.Sin(2*math.Pi*440*float64(i)/44100))
+		samples[2*i+1] = int16(i%2000 - 1000)
+	}
+	samples[0] = math.MinInt16
+	samples[1] = math.MaxInt16
+
+	filename := "test_output.flac"
+	defer os.Remove(filename) // Cleanup after test
+
+	if err := SaveFlac(filename, samples, 44100, 2); err != nil {
+		t.Fatalf("Failed to save FLAC file: %v", err)
+	}
+
+	loaded, sampleRate, err := LoadFlac(filename)
+	if err != nil {
+		t.Fatalf("Failed to load FLAC file: %v", err)
+	}
+	if sampleRate != 44100 {
+		t.Errorf("Expected sample rate 44100, got %d", sampleRate)
+	}
+	if len(loaded) != len(samples) {
+		t.Fatalf("Expected %d samples, got %d", len(samples), len(loaded))
+	}
+	for i, v := range loaded {
+		if v != samples[i] {
+			t.Fatalf("Expected sample %d to be %d, got %d", i, samples[i], v)
+		}
+	}
+}
+
+func TestFlacMono(t *testing.T) {
+	samples := []int16{1000, -1000, 2000, -2000, 3000, -3000, 4000, -4000, 5000, -5000, 6000, -6000, 7000, -7000, 8000, -8000}
+	filename := "test_output_mono.flac"
+	defer os.Remove(filename) // Cleanup after test
+
+	if err := SaveFlac(filename, samples, 22050, 1); err != nil {
+		t.Fatalf("Failed to save FLAC file: %v", err)
+	}
+
+	loaded, _, err := LoadFlac(filename)
+	if err != nil {
+		t.Fatalf("Failed to load FLAC file: %v", err)
+	}
+	// Mono files are loaded as stereo
+	if len(loaded) != 2*len(samples) {
+		t.Fatalf("Expected %d samples, got %d", 2*len(samples), len(loaded))
+	}
+	for i, sample := range samples {
+		if loaded[2*i] != sample || loaded[2*i+1] != sample {
+			t.Errorf("Expected both channels of frame %d to be %d, got %d and %d", i, sample, loaded[2*i], loaded[2*i+1])
+		}
+	}
+}
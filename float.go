@@ -0,0 +1,62 @@
+package mixorama
+
+import (
+	"math"
+	"math/rand"
+)
+
+// float32Scale is the scale between int16 samples and float32 samples, so that full scale is -1.0 to 1.0
+const float32Scale = 32768.0
+
+// ToFloat32 converts int16 samples to float32 samples in the range -1.0 to 1.0.
+// Effects can be chained on float32 samples without clipping in between, before converting back with ToInt16.
+func ToFloat32(samples []int16) []float32 {
+	floatSamples := make([]float32, len(samples))
+	for i, sample := range samples {
+		floatSamples[i] = float32(float64(sample) / float32Scale)
+	}
+	return floatSamples
+}
+
+// ToInt16 converts float32 samples in the range -1.0 to 1.0 to int16 samples.
+// Values outside of the range are clamped, and NaN is converted to 0.
+func ToInt16(samples []float32) []int16 {
+	intSamples := make([]int16, len(samples))
+	for i, sample := range samples {
+		if math.IsNaN(float64(sample)) {
+			continue
+		}
+		intSamples[i] = clampToInt16(float64(sample) * float32Scale)
+	}
+	return intSamples
+}
+
+// Dither quantizes float64 samples in the range -1.0 to 1.0 to targetBits bits, adding TPDF (triangular probability
+// density function) dither first, so that the quantization error becomes uncorrelated noise instead of distortion.
+// The result is returned as int16 samples, where only the targetBits most significant bits are used.
+// targetBits is limited to the range 1 to 16. The dither noise is generated with a fixed seed, so the output is
+// the same for the same input.
+func Dither(samples []float64, targetBits int) []int16 {
+	if targetBits < 1 {
+		targetBits = 1
+	} else if targetBits > 16 {
+		targetBits = 16
+	}
+
+	// step is the size of one least significant bit of the target bit depth, in int16 units
+	step := float64(int(1) << (16 - targetBits))
+	r := rand.New(rand.NewSource(1))
+
+	ditheredSamples := make([]int16, len(samples))
+	for i, sample := range samples {
+		if math.IsNaN(sample) {
+			continue
+		}
+		// The sum of two uniform values gives triangular noise with a peak amplitude of one step
+		noise := (r.Float64() - r.Float64()) * step
+		quantized := math.Round((sample*float32Scale+noise)/step) * step
+		ditheredSamples[i] = clampToInt16(quantized)
+	}
+
+	return ditheredSamples
+}
@@ -0,0 +1,103 @@
+package mixorama
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFloat32RoundTrip(t *testing.T) {
+	samples := []int16{0, 1, -1, 12345, -12345, math.MaxInt16, math.MinInt16}
+	floatSamples := ToFloat32(samples)
+	if floatSamples[6] != -1 {
+		t.Errorf("Expected math.MinInt16 to convert to -1.0, got %f", floatSamples[6])
+	}
+	for i, v := range ToInt16(floatSamples) {
+		if v != samples[i] {
+			t.Errorf("Expected sample %d to round-trip to %d, got %d", i, samples[i], v)
+		}
+	}
+}
+
+func TestToInt16Clamping(t *testing.T) {
+	result := ToInt16([]float32{2, -2, float32(math.NaN())})
+	if result[0] != math.MaxInt16 || result[1] != math.MinInt16 || result[2] != 0 {
+		t.Errorf("Expected [%d %d 0], got %v", math.MaxInt16, math.MinInt16, result)
+	}
+}
+
+func TestFloat32NoIntermediateClipping(t *testing.T) {
+	samples := createSineWave(440, 20000, 44100, 1000)
+
+	// Boosting by 4 and then attenuating by 4 clips in between when done on int16 samples
+	clipped := ApplyGain(ApplyGain(samples, 4), 0.25)
+	if FindPeakAmplitude(clipped) > 8192 {
+		t.Fatalf("Expected the int16 processing to clip, got peak %d", FindPeakAmplitude(clipped))
+	}
+
+	floatSamples := ToFloat32(samples)
+	for i := range floatSamples {
+		floatSamples[i] *= 4
+	}
+	for i := range floatSamples {
+		floatSamples[i] *= 0.25
+	}
+	for i, v := range ToInt16(floatSamples) {
+		if v != samples[i] {
+			t.Fatalf("Expected float processing to preserve sample %d as %d, got %d", i, samples[i], v)
+		}
+	}
+}
+
+// harmonicRatio returns the magnitude of the third harmonic of the frequency, relative to the fundamental
+func harmonicRatio(samples []int16, sampleRate int, frequency float64) float64 {
+	magnitudes, frequencies := AnalyzeSpectrum(samples, sampleRate)
+	fundamental, harmonic := 0.0, 0.0
+	for i, magnitude := range magnitudes {
+		if math.Abs(frequencies[i]-frequency) < 5 {
+			fundamental = math.Max(fundamental, magnitude)
+		}
+		if math.Abs(frequencies[i]-3*frequency) < 5 {
+			harmonic = math.Max(harmonic, magnitude)
+		}
+	}
+	return harmonic / fundamental
+}
+
+func TestDither(t *testing.T) {
+	sampleRate := 44100
+
+	// A very quiet sine wave, with an amplitude of 1.5 steps at 8 bits
+	quiet := make([]float64, sampleRate)
+	for i := range quiet {
+		quiet[i] = 1.5 * 256 / float32Scale * math.Sin(2*math.Pi*1000*float64(i)/float64(sampleRate))
+	}
+
+	truncated := make([]int16, len(quiet))
+	for i, v := range quiet {
+		truncated[i] = int16(math.Round(v*float32Scale/256) * 256)
+	}
+	dithered := Dither(quiet, 8)
+
+	for i, v := range dithered {
+		if v%256 != 0 {
+			t.Fatalf("Expected the dithered samples to be quantized to 8 bits, got %d at index %d", v, i)
+		}
+	}
+
+	// Without dither, quantization adds strong harmonic distortion, while dither turns it into a flat noise floor
+	without := harmonicRatio(truncated, sampleRate, 1000)
+	with := harmonicRatio(dithered, sampleRate, 1000)
+	if without < 0.1 {
+		t.Fatalf("Expected quantization without dither to add harmonics, got a ratio of %.4f", without)
+	}
+	if with > without/10 {
+		t.Errorf("Expected dither to remove the harmonic distortion, got a ratio of %.4f (without dither: %.4f)", with, without)
+	}
+}
+
+func TestDitherClamping(t *testing.T) {
+	result := Dither([]float64{2, -2, math.NaN()}, 16)
+	if result[0] != math.MaxInt16 || result[1] != math.MinInt16 || result[2] != 0 {
+		t.Errorf("Expected [%d %d 0], got %v", math.MaxInt16, math.MinInt16, result)
+	}
+}
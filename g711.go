@@ -0,0 +1,130 @@
+package mixorama
+
+const (
+	// muLawBias is added to the magnitude before mu-law encoding, so that every segment starts at a power of two
+	muLawBias = 0x84
+	// muLawClip is the largest magnitude that can be mu-law encoded after the bias is added
+	muLawClip = 32635
+)
+
+// EncodeMuLaw compresses 16-bit samples to 8-bit G.711 mu-law, which is used for telephony in North America and Japan.
+// Each sample is encoded as one byte, with a logarithmic scale that keeps more precision for quiet samples.
+func EncodeMuLaw(samples []int16) []byte {
+	encoded := make([]byte, len(samples))
+	for i, sample := range samples {
+		encoded[i] = linearToMuLaw(sample)
+	}
+	return encoded
+}
+
+// DecodeMuLaw expands 8-bit G.711 mu-law bytes to 16-bit samples
+func DecodeMuLaw(data []byte) []int16 {
+	decoded := make([]int16, len(data))
+	for i, b := range data {
+		decoded[i] = muLawToLinear(b)
+	}
+	return decoded
+}
+
+// EncodeALaw compresses 16-bit samples to 8-bit G.711 A-law, which is used for telephony in Europe and most of
+// the rest of the world. Each sample is encoded as one byte, with a logarithmic scale like mu-law.
+func EncodeALaw(samples []int16) []byte {
+	encoded := make([]byte, len(samples))
+	for i, sample := range samples {
+		encoded[i] = linearToALaw(sample)
+	}
+	return encoded
+}
+
+// DecodeALaw expands 8-bit G.711 A-law bytes to 16-bit samples
+func DecodeALaw(data []byte) []int16 {
+	decoded := make([]int16, len(data))
+	for i, b := range data {
+		decoded[i] = aLawToLinear(b)
+	}
+	return decoded
+}
+
+// linearToMuLaw encodes a single sample as mu-law
+func linearToMuLaw(sample int16) byte {
+	magnitude := int(sample)
+	sign := 0
+	if magnitude < 0 {
+		magnitude = -magnitude
+		sign = 0x80
+	}
+	if magnitude > muLawClip {
+		magnitude = muLawClip
+	}
+	magnitude += muLawBias
+
+	// The exponent is the position of the highest set bit, above bit 7
+	exponent := 7
+	for mask := 0x4000; magnitude&mask == 0 && exponent > 0; mask >>= 1 {
+		exponent--
+	}
+	mantissa := (magnitude >> (exponent + 3)) & 0x0F
+
+	// The bits are inverted, as G.711 specifies
+	return ^byte(sign | exponent<<4 | mantissa)
+}
+
+// muLawToLinear decodes a single mu-law byte
+func muLawToLinear(b byte) int16 {
+	b = ^b
+	exponent := int(b>>4) & 0x07
+	mantissa := int(b) & 0x0F
+	magnitude := (((mantissa << 3) + muLawBias) << exponent) - muLawBias
+	if b&0x80 != 0 {
+		return int16(-magnitude)
+	}
+	return int16(magnitude)
+}
+
+// linearToALaw encodes a single sample as A-law
+func linearToALaw(sample int16) byte {
+	// A-law works on 13-bit samples
+	value := int(sample) >> 3
+	mask := 0xD5
+	if value < 0 {
+		mask = 0x55
+		value = -value - 1
+	}
+
+	// The segment is the position of the highest set bit, above bit 4
+	segment := 0
+	for segmentEnd := 0x1F; value > segmentEnd && segment < 8; segmentEnd = segmentEnd<<1 | 1 {
+		segment++
+	}
+	if segment >= 8 {
+		return byte(0x7F ^ mask)
+	}
+
+	encoded := segment << 4
+	if segment < 2 {
+		encoded |= (value >> 1) & 0x0F
+	} else {
+		encoded |= (value >> segment) & 0x0F
+	}
+	// Every other bit is inverted, as G.711 specifies
+	return byte(encoded ^ mask)
+}
+
+// aLawToLinear decodes a single A-law byte, to the middle of the range of samples that it represents
+func aLawToLinear(b byte) int16 {
+	b ^= 0x55
+	magnitude := (int(b) & 0x0F) << 4
+	segment := (int(b) & 0x70) >> 4
+	switch segment {
+	case 0:
+		magnitude += 8
+	case 1:
+		magnitude += 0x108
+	default:
+		magnitude = (magnitude + 0x108) << (segment - 1)
+	}
+	if b&0x80 != 0 {
+		return int16(magnitude)
+	}
+	return int16(-magnitude)
+}
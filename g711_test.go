@@ -0,0 +1,69 @@
+package mixorama
+
+import (
+	"math"
+	"testing"
+)
+
+// checkCompanding checks that decoding the encoded samples gives the samples back, within the quantization error
+// of the logarithmic scale, which is at most about 1/16 of the amplitude
+func checkCompanding(t *testing.T, name string, encode func([]int16) []byte, decode func([]byte) []int16) {
+	samples := make([]int16, 0, 65536)
+	for v := math.MinInt16; v <= math.MaxInt16; v++ {
+		samples = append(samples, int16(v))
+	}
+
+	encoded := encode(samples)
+	if len(encoded) != len(samples) {
+		t.Fatalf("Expected %d %s bytes, got %d", len(samples), name, len(encoded))
+	}
+	decoded := decode(encoded)
+	if len(decoded) != len(samples) {
+		t.Fatalf("Expected %d decoded %s samples, got %d", len(samples), name, len(decoded))
+	}
+
+	for i, sample := range samples {
+		diff := math.Abs(float64(decoded[i]) - float64(sample))
+		if limit := math.Abs(float64(sample))/16 + 16; diff > limit {
+			t.Fatalf("Expected %s to decode %d within %.0f, got %d", name, sample, limit, decoded[i])
+		}
+	}
+
+	// Quiet samples keep more precision than loud samples
+	quietError := math.Abs(float64(decode(encode([]int16{100}))[0]) - 100)
+	loudError := math.Abs(float64(decode(encode([]int16{30100}))[0]) - 30100)
+	if quietError >= loudError && loudError != 0 {
+		t.Errorf("Expected %s to be more precise for quiet samples, got errors %.0f and %.0f", name, quietError, loudError)
+	}
+}
+
+func TestMuLaw(t *testing.T) {
+	checkCompanding(t, "mu-law", EncodeMuLaw, DecodeMuLaw)
+
+	// Silence is encoded as 0xFF, and decoding every byte and encoding it again gives the same byte
+	if encoded := EncodeMuLaw([]int16{0}); encoded[0] != 0xFF {
+		t.Errorf("Expected silence to be encoded as 0xFF, got 0x%02X", encoded[0])
+	}
+	for b := 0; b < 256; b++ {
+		if b == 0x7F {
+			// Both 0x7F and 0xFF decode to 0, which is encoded as 0xFF
+			continue
+		}
+		if encoded := EncodeMuLaw(DecodeMuLaw([]byte{byte(b)})); encoded[0] != byte(b) {
+			t.Errorf("Expected mu-law byte 0x%02X to survive a round trip, got 0x%02X", b, encoded[0])
+		}
+	}
+}
+
+func TestALaw(t *testing.T) {
+	checkCompanding(t, "A-law", EncodeALaw, DecodeALaw)
+
+	if encoded := EncodeALaw([]int16{0}); encoded[0] != 0xD5 {
+		t.Errorf("Expected silence to be encoded as 0xD5, got 0x%02X", encoded[0])
+	}
+	for b := 0; b < 256; b++ {
+		if encoded := EncodeALaw(DecodeALaw([]byte{byte(b)})); encoded[0] != byte(b) {
+			t.Errorf("Expected A-law byte 0x%02X to survive a round trip, got 0x%02X", b, encoded[0])
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package mixorama
+
+import "math"
+
+// RMSLevel returns the root-mean-square amplitude of the samples, or 0 if there are no samples
+func RMSLevel(samples []int16) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sumSquares := 0.0
+	for _, sample := range samples {
+		sumSquares += float64(sample) * float64(sample)
+	}
+	return math.Sqrt(sumSquares / float64(len(samples)))
+}
+
+// RMSLevelDB returns the root-mean-square level of the samples in dBFS, where a full scale square wave is 0 dBFS.
+// Silent or empty input returns -Inf.
+func RMSLevelDB(samples []int16) float64 {
+	rms := RMSLevel(samples)
+	if rms == 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(rms/math.MaxInt16)
+}
+
+// LevelStats returns the peak amplitude, the RMS level and the crest factor (peak divided by RMS) of the samples.
+// A high crest factor means that the peaks are far above the average level, which compression can reduce.
+// For silent or empty input, the crest factor is 0.
+func LevelStats(samples []int16) (peak int16, rms float64, crestFactor float64) {
+	peak = FindPeakAmplitude(samples)
+	rms = RMSLevel(samples)
+	if rms > 0 {
+		crestFactor = float64(peak) / rms
+	}
+	return peak, rms, crestFactor
+}
+
+// CountClippedSamples returns the number of samples that are at full scale (math.MaxInt16 or math.MinInt16),
+// which usually means that the signal was clipped
+func CountClippedSamples(samples []int16) int {
+	count := 0
+	for _, sample := range samples {
+		if isClipped(sample) {
+			count++
+		}
+	}
+	return count
+}
+
+// HasClipping returns true if any of the samples are at full scale
+func HasClipping(samples []int16) bool {
+	for _, sample := range samples {
+		if isClipped(sample) {
+			return true
+		}
+	}
+	return false
+}
+
+// isClipped returns true if the sample is at full scale
+func isClipped(sample int16) bool {
+	return sample == math.MaxInt16 || sample == math.MinInt16
+}
+
+// RepairClipping reconstructs the peaks of a clipped signal. Runs of two or more consecutive samples at full scale
+// are replaced by a cubic curve through the two samples before and the two samples after the run.
+// Since the reconstructed peaks are above full scale, the whole signal is then scaled down so that the peak is
+// one step below full scale, where it is not counted by CountClippedSamples.
+// Runs at the very start or end of the samples, without two neighbours on each side, are left as they are.
+func RepairClipping(samples []int16) []int16 {
+	l := len(samples)
+	repaired := make([]float64, l)
+	for i, sample := range samples {
+		repaired[i] = float64(sample)
+	}
+
+	repairedRun := false
+	for start := 0; start < l; start++ {
+		if !isClipped(samples[start]) {
+			continue
+		}
+		end := start
+		for end+1 < l && samples[end+1] == samples[start] {
+			end++
+		}
+		if end > start && start >= 2 && end+2 < l {
+			// Lagrange interpolation through the points at start-2, start-1, end+1 and end+2
+			xs := [4]float64{float64(start - 2), float64(start - 1), float64(end + 1), float64(end + 2)}
+			ys := [4]float64{repaired[start-2], repaired[start-1], repaired[end+1], repaired[end+2]}
+			for i := start; i <= end; i++ {
+				x := float64(i)
+				value := 0.0
+				for j := 0; j < 4; j++ {
+					term := ys[j]
+					for k := 0; k < 4; k++ {
+						if k != j {
+							term *= (x - xs[k]) / (xs[j] - xs[k])
+						}
+					}
+					value += term
+				}
+				// The true peak is at least as large as the clipped one
+				if math.Abs(value) > math.Abs(repaired[i]) && math.Signbit(value) == math.Signbit(repaired[i]) {
+					repaired[i] = value
+					repairedRun = true
+				}
+			}
+		}
+		start = end
+	}
+
+	scale := 1.0
+	if repairedRun {
+		peak := 0.0
+		for _, value := range repaired {
+			peak = math.Max(peak, math.Abs(value))
+		}
+		scale = (math.MaxInt16 - 1) / peak
+	}
+
+	output := make([]int16, l)
+	for i, value := range repaired {
+		output[i] = clampToInt16(value * scale)
+	}
+	return output
+}
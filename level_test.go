@@ -0,0 +1,127 @@
+package mixorama
+
+import (
+	"math"
+	"testing"
+)
+
+// Helper function to create a square wave for testing
+func createSquareWave(amplitude int16, period, numSamples int) []int16 {
+	waveform := make([]int16, numSamples)
+	for i := 0; i < numSamples; i++ {
+		if i%period < period/2 {
+			waveform[i] = amplitude
+		} else {
+			waveform[i] = -amplitude
+		}
+	}
+	return waveform
+}
+
+func TestRMSLevelSquareWave(t *testing.T) {
+	rms := RMSLevel(createSquareWave(8000, 100, 10000))
+	if math.Abs(rms-8000) > 0.001 {
+		t.Errorf("Expected the RMS of a square wave to equal its amplitude (8000), got %.2f", rms)
+	}
+
+	if db := RMSLevelDB(createSquareWave(math.MaxInt16, 100, 10000)); math.Abs(db) > 0.001 {
+		t.Errorf("Expected a full scale square wave to be 0 dBFS, got %.2f", db)
+	}
+}
+
+func TestRMSLevelSineWave(t *testing.T) {
+	// 100 full periods of a 441 Hz sine wave
+	rms := RMSLevel(createSineWave(441, 10000, 44100, 10000))
+	expected := 10000 / math.Sqrt2
+	if math.Abs(rms-expected) > 5 {
+		t.Errorf("Expected the RMS of a sine wave to be about %.2f, got %.2f", expected, rms)
+	}
+}
+
+func TestRMSLevelEmpty(t *testing.T) {
+	if rms := RMSLevel(nil); rms != 0 {
+		t.Errorf("Expected 0 for empty input, got %.2f", rms)
+	}
+	if db := RMSLevelDB(nil); !math.IsInf(db, -1) {
+		t.Errorf("Expected -Inf dB for empty input, got %.2f", db)
+	}
+}
+
+func TestLevelStats(t *testing.T) {
+	peak, rms, crestFactor := LevelStats(createSineWave(441, 10000, 44100, 10000))
+	if peak != 10000 {
+		t.Errorf("Expected the peak of the sine wave to be 10000, got %d", peak)
+	}
+	if math.Abs(rms-10000/math.Sqrt2) > 5 {
+		t.Errorf("Expected the RMS of the sine wave to be about %.2f, got %.2f", 10000/math.Sqrt2, rms)
+	}
+	if math.Abs(crestFactor-math.Sqrt2) > 0.01 {
+		t.Errorf("Expected the crest factor of a sine wave to be about %.4f, got %.4f", math.Sqrt2, crestFactor)
+	}
+
+	if _, _, crestFactor := LevelStats(createSquareWave(8000, 100, 10000)); math.Abs(crestFactor-1) > 0.001 {
+		t.Errorf("Expected the crest factor of a square wave to be 1, got %.4f", crestFactor)
+	}
+
+	if _, _, crestFactor := LevelStats(make([]int16, 100)); crestFactor != 0 {
+		t.Errorf("Expected the crest factor of silence to be 0, got %.4f", crestFactor)
+	}
+}
+
+func TestCountClippedSamples(t *testing.T) {
+	samples := []int16{0, math.MaxInt16, 1000, math.MinInt16, -1000, math.MaxInt16 - 1, math.MaxInt16}
+	if count := CountClippedSamples(samples); count != 3 {
+		t.Errorf("Expected 3 clipped samples, got %d", count)
+	}
+	if !HasClipping(samples) {
+		t.Error("Expected clipping to be detected")
+	}
+
+	clean := createSineWave(440, 10000, 44100, 1000)
+	if count := CountClippedSamples(clean); count != 0 {
+		t.Errorf("Expected 0 clipped samples, got %d", count)
+	}
+	if HasClipping(clean) {
+		t.Error("Expected no clipping to be detected")
+	}
+}
+
+func TestRepairClipping(t *testing.T) {
+	sampleRate := 44100
+	// A sine wave with an amplitude of 40000 has flat tops at full scale
+	clipped := make([]int16, 1000)
+	for i := range clipped {
+		clipped[i] = clampToInt16(40000 * math.Sin(2*math.Pi*441*float64(i)/float64(sampleRate)))
+	}
+	if CountClippedSamples(clipped) < 100 {
+		t.Fatalf("Expected the test signal to be clipped, got %d clipped samples", CountClippedSamples(clipped))
+	}
+
+	repaired := RepairClipping(clipped)
+	if len(repaired) != len(clipped) {
+		t.Fatalf("Expected %d samples, got %d", len(clipped), len(repaired))
+	}
+	if CountClippedSamples(repaired) != 0 {
+		t.Errorf("Expected the flat tops to be replaced, got %d samples at full scale", CountClippedSamples(repaired))
+	}
+
+	// The repaired signal should be close to the unclipped sine wave, scaled down to fit
+	scale := float64(FindPeakAmplitude(repaired)) / 40000
+	maxClippedError, maxRepairedError := 0.0, 0.0
+	for i := range clipped {
+		expected := 40000 * scale * math.Sin(2*math.Pi*441*float64(i)/float64(sampleRate))
+		maxClippedError = math.Max(maxClippedError, math.Abs(float64(clipped[i])*scale-expected))
+		maxRepairedError = math.Max(maxRepairedError, math.Abs(float64(repaired[i])-expected))
+	}
+	if maxRepairedError*5 > maxClippedError {
+		t.Errorf("Expected the repaired peaks to be rounded like the original sine wave, got a max error of %.0f (clipped: %.0f)", maxRepairedError, maxClippedError)
+	}
+
+	// Signals without clipping are not changed
+	clean := createSineWave(441, 10000, sampleRate, 1000)
+	for i, v := range RepairClipping(clean) {
+		if v != clean[i] {
+			t.Fatalf("Expected an unclipped signal to be unchanged, got %d instead of %d at index %d", v, clean[i], i)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package mixorama
+
+import "math"
+
+// kWeightingFilters returns the two filter stages of the K-weighting curve from ITU-R BS.1770,
+// a high shelf that models the acoustic effect of the head, followed by a high-pass filter.
+func kWeightingFilters(sampleRate int) (*biquad, *biquad) {
+	fs := float64(sampleRate)
+
+	// Stage 1: high shelf
+	f0 := 1681.974450955533
+	gainDB := 3.999843853973347
+	q := 0.7071752369554196
+	k := math.Tan(math.Pi * f0 / fs)
+	vh := math.Pow(10, gainDB/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+	shelf := &biquad{
+		b0: (vh + vb*k/q + k*k) / a0,
+		b1: 2 * (k*k - vh) / a0,
+		b2: (vh - vb*k/q + k*k) / a0,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+
+	// Stage 2: high-pass filter
+	f0 = 38.13547087602444
+	q = 0.5003270373238773
+	k = math.Tan(math.Pi * f0 / fs)
+	a0 = 1 + k/q + k*k
+	highPass := &biquad{
+		b0: 1,
+		b1: -2,
+		b2: 1,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+
+	return shelf, highPass
+}
+
+// IntegratedLoudness measures the integrated loudness of interleaved samples in LUFS, as specified by EBU R128
+// and ITU-R BS.1770. The samples are K-weighted and measured in gated blocks of 400 ms with 75% overlap.
+// All channels are weighted equally. -Inf is returned if the samples are shorter than one block or are silent.
+func IntegratedLoudness(samples []int16, sampleRate, numChannels int) float64 {
+	if sampleRate <= 0 || numChannels <= 0 {
+		return math.Inf(-1)
+	}
+
+	numFrames := len(samples) / numChannels
+	blockSize := int(0.4 * float64(sampleRate))
+	stepSize := blockSize / 4
+	if blockSize == 0 || numFrames < blockSize {
+		return math.Inf(-1)
+	}
+
+	// K-weight each channel and store the squared values, in full scale units
+	squared := make([][]float64, numChannels)
+	for c := 0; c < numChannels; c++ {
+		shelf, highPass := kWeightingFilters(sampleRate)
+		squared[c] = make([]float64, numFrames)
+		for i := 0; i < numFrames; i++ {
+			x := float64(samples[i*numChannels+c]) / 32768
+			y := highPass.process(shelf.process(x))
+			squared[c][i] = y * y
+		}
+	}
+
+	// Calculate the mean square of each block, summed over the channels
+	var blockPowers []float64
+	for start := 0; start+blockSize <= numFrames; start += stepSize {
+		power := 0.0
+		for c := 0; c < numChannels; c++ {
+			sum := 0.0
+			for i := start; i < start+blockSize; i++ {
+				sum += squared[c][i]
+			}
+			power += sum / float64(blockSize)
+		}
+		blockPowers = append(blockPowers, power)
+	}
+
+	loudness := func(power float64) float64 {
+		return -0.691 + 10*math.Log10(power)
+	}
+
+	// gatedMean returns the mean power of the blocks that are louder than the given threshold
+	gatedMean := func(threshold float64) float64 {
+		sum := 0.0
+		count := 0
+		for _, power := range blockPowers {
+			if power > 0 && loudness(power) > threshold {
+				sum += power
+				count++
+			}
+		}
+		if count == 0 {
+			return 0
+		}
+		return sum / float64(count)
+	}
+
+	// Apply the absolute gate at -70 LUFS, and then the relative gate at 10 LU below the absolutely gated loudness
+	absoluteMean := gatedMean(-70)
+	if absoluteMean == 0 {
+		return math.Inf(-1)
+	}
+	relativeMean := gatedMean(loudness(absoluteMean) - 10)
+	if relativeMean == 0 {
+		return math.Inf(-1)
+	}
+
+	return loudness(relativeMean)
+}
+
+// NormalizeLUFS applies the gain needed to bring mono samples to the target integrated loudness, in LUFS.
+// If the loudness can not be measured, because the samples are silent or too short, an unchanged copy is returned.
+func NormalizeLUFS(samples []int16, sampleRate int, targetLUFS float64) []int16 {
+	return NormalizeLUFSChannels(samples, sampleRate, 1, targetLUFS)
+}
+
+// NormalizeLUFSChannels applies the gain needed to bring interleaved samples with the given number of channels
+// to the target integrated loudness, in LUFS. If the loudness can not be measured, an unchanged copy is returned.
+func NormalizeLUFSChannels(samples []int16, sampleRate, numChannels int, targetLUFS float64) []int16 {
+	normalizedSamples := make([]int16, len(samples))
+
+	current := IntegratedLoudness(samples, sampleRate, numChannels)
+	if math.IsInf(current, -1) {
+		copy(normalizedSamples, samples)
+		return normalizedSamples
+	}
+
+	gain := math.Pow(10, (targetLUFS-current)/20)
+	for i, sample := range samples {
+		normalizedSamples[i] = clampToInt16(float64(sample) * gain)
+	}
+
+	return normalizedSamples
+}
+
+// MixLoudnessMatched brings each of the mono tracks to targetLUFS with NormalizeLUFS, so that no track dominates,
+// and then mixes them with LinearSummationAutoScale, which scales the mix down if the sum would clip.
+// Since each track is clamped when it is normalized, targetLUFS should leave some headroom, like -23 LUFS does.
+// Tracks that are silent or too short to be measured are mixed in unchanged.
+func MixLoudnessMatched(sampleRate int, targetLUFS float64, samples ...[]int16) ([]int16, error) {
+	normalized := make([][]int16, len(samples))
+	for i, sample := range samples {
+		normalized[i] = NormalizeLUFS(sample, sampleRate, targetLUFS)
+	}
+	return LinearSummationAutoScale(normalized...)
+}
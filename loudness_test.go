@@ -0,0 +1,66 @@
+package mixorama
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegratedLoudnessReference(t *testing.T) {
+	// A full scale 997 Hz sine wave in one channel measures about -3.01 LUFS
+	samples := createSineWave(997, math.MaxInt16, 48000, 48000*2)
+	loudness := IntegratedLoudness(samples, 48000, 1)
+	if math.Abs(loudness-(-3.01)) > 0.1 {
+		t.Errorf("Expected about -3.01 LUFS, got %.2f", loudness)
+	}
+}
+
+func TestNormalizeLUFS(t *testing.T) {
+	sampleRate := 44100
+	samples := createSineWave(440, 3000, sampleRate, sampleRate*3)
+
+	before := IntegratedLoudness(samples, sampleRate, 1)
+	normalized := NormalizeLUFS(samples, sampleRate, -16)
+	after := IntegratedLoudness(normalized, sampleRate, 1)
+
+	if math.Abs(before-(-16)) < 1 {
+		t.Fatalf("Expected the test signal to start away from the target, got %.2f LUFS", before)
+	}
+	if math.Abs(after-(-16)) > 0.5 {
+		t.Errorf("Expected the normalized loudness to be within 0.5 LU of -16 LUFS, got %.2f", after)
+	}
+}
+
+func TestIntegratedLoudnessSilence(t *testing.T) {
+	if loudness := IntegratedLoudness(make([]int16, 44100), 44100, 1); !math.IsInf(loudness, -1) {
+		t.Errorf("Expected -Inf LUFS for silence, got %.2f", loudness)
+	}
+	// Too short to measure
+	if loudness := IntegratedLoudness(createTestWaveform(1000, 100), 44100, 1); !math.IsInf(loudness, -1) {
+		t.Errorf("Expected -Inf LUFS for input shorter than one block, got %.2f", loudness)
+	}
+}
+
+func TestMixLoudnessMatched(t *testing.T) {
+	sampleRate := 48000
+	quiet := createSineWave(440, 1000, sampleRate, sampleRate)
+	loud := createSineWave(1000, 20000, sampleRate, sampleRate)
+
+	mixed, err := MixLoudnessMatched(sampleRate, -23, quiet, loud)
+	if err != nil {
+		t.Fatalf("Error in MixLoudnessMatched: %v", err)
+	}
+	if len(mixed) != len(quiet) {
+		t.Fatalf("Expected %d samples, got %d", len(quiet), len(mixed))
+	}
+
+	// Both tracks should contribute comparably, within the small difference that the K-weighting makes
+	quietLevel := DetectFrequencyMagnitude(mixed, sampleRate, 440)
+	loudLevel := DetectFrequencyMagnitude(mixed, sampleRate, 1000)
+	if ratio := loudLevel / quietLevel; ratio < 0.7 || ratio > 1.4 {
+		t.Errorf("Expected the tracks to contribute comparably, got magnitudes %.2f and %.2f", quietLevel, loudLevel)
+	}
+
+	if _, err := MixLoudnessMatched(sampleRate, -23, quiet, loud[:100]); err == nil {
+		t.Error("Expected error for mismatched sample lengths")
+	}
+}
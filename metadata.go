@@ -0,0 +1,206 @@
+package mixorama
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// infoKeys maps the IDs of the LIST/INFO subchunks to the keys used by ReadWavMetadata
+var infoKeys = map[string]string{
+	"IART": "artist",
+	"ICMT": "comments",
+	"ICOP": "copyright",
+	"ICRD": "date",
+	"IENG": "engineer",
+	"IGNR": "genre",
+	"IKEY": "keywords",
+	"IMED": "medium",
+	"INAM": "title",
+	"IPRD": "album",
+	"ISBJ": "subject",
+	"ISFT": "software",
+	"ISRC": "source",
+	"ITCH": "technician",
+	"ITRK": "track",
+}
+
+// bextFields lists the keys and lengths of the text fields at the start of a bext (Broadcast Wave) chunk
+var bextFields = []struct {
+	key    string
+	length int
+}{
+	{"description", 256},
+	{"originator", 32},
+	{"originator_reference", 32},
+	{"origination_date", 10},
+	{"origination_time", 8},
+}
+
+// trimText removes the NUL padding and surrounding whitespace from a text field
+func trimText(data []byte) string {
+	if i := strings.IndexByte(string(data), 0); i >= 0 {
+		data = data[:i]
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// parseInfoList adds the text fields of the body of a LIST/INFO chunk to the metadata
+func parseInfoList(body []byte, metadata map[string]string) {
+	offset := 4 // skip the "INFO" list type
+	for offset+8 <= len(body) {
+		id := string(body[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(body[offset+4 : offset+8]))
+		start := offset + 8
+		if start+size > len(body) {
+			size = len(body) - start
+		}
+		key, ok := infoKeys[id]
+		if !ok {
+			key = id
+		}
+		if value := trimText(body[start : start+size]); value != "" {
+			metadata[key] = value
+		}
+		// Subchunks are padded to an even number of bytes
+		offset = start + size + size%2
+	}
+}
+
+// parseBext adds the text fields of the body of a bext chunk to the metadata
+func parseBext(body []byte, metadata map[string]string) {
+	offset := 0
+	for _, field := range bextFields {
+		if offset+field.length > len(body) {
+			return
+		}
+		if value := trimText(body[offset : offset+field.length]); value != "" {
+			metadata[field.key] = value
+		}
+		offset += field.length
+	}
+}
+
+// appendChunk appends a RIFF chunk with the given ID and body, padded to an even number of bytes
+func appendChunk(data []byte, id string, body []byte) []byte {
+	data = append(data, id...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(body)))
+	data = append(data, body...)
+	if len(body)%2 != 0 {
+		data = append(data, 0)
+	}
+	return data
+}
+
+// ReadWavMetadata reads the text tags of the LIST/INFO and bext chunks of a .wav file. The INFO tags use keys such as
+// "title", "artist" and "comments", and tags that are not known are returned with their chunk ID as the key.
+// The bext tags use keys such as "description" and "originator". The samples are not read.
+func ReadWavMetadata(filename string) (map[string]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	fileSize := info.Size()
+
+	header := make([]byte, 12)
+	if _, err := f.ReadAt(header, 0); err != nil {
+		return nil, errors.New("file is too short to be a wav file")
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return nil, errors.New("not a RIFF/WAVE file")
+	}
+
+	metadata := make(map[string]string)
+	offset := int64(12)
+	chunkHeader := make([]byte, 8)
+	for offset+8 <= fileSize {
+		if _, err := f.ReadAt(chunkHeader, offset); err != nil {
+			return nil, err
+		}
+		chunkID := string(chunkHeader[0:4])
+		chunkSize := int64(binary.LittleEndian.Uint32(chunkHeader[4:8]))
+		if offset+8+chunkSize > fileSize {
+			chunkSize = fileSize - offset - 8
+		}
+
+		if chunkID == "LIST" || chunkID == "bext" {
+			body := make([]byte, chunkSize)
+			if _, err := f.ReadAt(body, offset+8); err != nil {
+				return nil, err
+			}
+			if chunkID == "bext" {
+				parseBext(body, metadata)
+			} else if len(body) >= 4 && string(body[0:4]) == "INFO" {
+				parseInfoList(body, metadata)
+			}
+		}
+
+		// Chunks are padded to an even number of bytes
+		offset += 8 + chunkSize + chunkSize%2
+	}
+
+	return metadata, nil
+}
+
+// SaveWavWithMetadata saves a slice of interleaved int16 samples with the given number of channels as a .wav file,
+// with the given tags in a LIST/INFO chunk. The tags use the same keys as ReadWavMetadata returns, and a four
+// character chunk ID such as "IXYZ" can be used for other tags. Empty values are skipped. An error is returned
+// if two keys refer to the same chunk ID, such as "title" and "INAM".
+func SaveWavWithMetadata(filename string, samples []int16, sampleRate, numChannels int, meta map[string]string) error {
+	ids := make(map[string]string, len(infoKeys))
+	for id, key := range infoKeys {
+		ids[key] = id
+	}
+
+	// Collect the tags by chunk ID, in a predictable order
+	tags := make(map[string]string, len(meta))
+	keys := make(map[string]string, len(meta))
+	for key, value := range meta {
+		id, ok := ids[key]
+		if !ok {
+			if len(key) != 4 {
+				return fmt.Errorf("unknown metadata key: %s", key)
+			}
+			id = key
+		}
+		if other, ok := keys[id]; ok {
+			first, second := min(key, other), max(key, other)
+			return fmt.Errorf("metadata keys %s and %s both set the %s chunk", first, second, id)
+		}
+		keys[id] = key
+		if value != "" {
+			tags[id] = value
+		}
+	}
+	sortedIDs := make([]string, 0, len(tags))
+	for id := range tags {
+		sortedIDs = append(sortedIDs, id)
+	}
+	sort.Strings(sortedIDs)
+
+	buffer := &writeSeekBuffer{}
+	if err := EncodeWav(buffer, samples, sampleRate, numChannels); err != nil {
+		return err
+	}
+	data := buffer.data
+
+	if len(sortedIDs) > 0 {
+		info := []byte("INFO")
+		for _, id := range sortedIDs {
+			info = appendChunk(info, id, append([]byte(tags[id]), 0))
+		}
+		data = appendChunk(data, "LIST", info)
+		binary.LittleEndian.PutUint32(data[4:8], uint32(len(data)-8))
+	}
+
+	return os.WriteFile(filename, data, 0o644)
+}
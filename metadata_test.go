@@ -0,0 +1,133 @@
+package mixorama
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadWavMetadata(t *testing.T) {
+	var wavData bytes.Buffer
+	if err := EncodeWav(&wavData, []int16{1, 2, 3, 4}, 44100, 1); err != nil {
+		t.Fatalf("Failed to encode WAV data: %v", err)
+	}
+	data := wavData.Bytes()
+
+	// Add a LIST/INFO chunk with a title, an artist, a comment and an unknown tag
+	info := []byte("INFO")
+	info = appendChunk(info, "INAM", []byte("Test Title\x00"))
+	info = appendChunk(info, "IART", []byte("Test Artist\x00"))
+	info = appendChunk(info, "ICMT", []byte("A comment\x00"))
+	info = appendChunk(info, "IXYZ", []byte("Other\x00"))
+	data = appendChunk(data, "LIST", info)
+
+	// Add a bext chunk with a description and an originator
+	bext := make([]byte, 602)
+	copy(bext, "Broadcast description")
+	copy(bext[256:], "Mixorama")
+	data = appendChunk(data, "bext", bext)
+
+	binary.LittleEndian.PutUint32(data[4:8], uint32(len(data)-8))
+	filename := filepath.Join(t.TempDir(), "metadata.wav")
+	if err := os.WriteFile(filename, data, 0o644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	metadata, err := ReadWavMetadata(filename)
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %v", err)
+	}
+	expected := map[string]string{
+		"title":       "Test Title",
+		"artist":      "Test Artist",
+		"comments":    "A comment",
+		"IXYZ":        "Other",
+		"description": "Broadcast description",
+		"originator":  "Mixorama",
+	}
+	for key, value := range expected {
+		if metadata[key] != value {
+			t.Errorf("Expected %s to be %q, got %q", key, value, metadata[key])
+		}
+	}
+	if len(metadata) != len(expected) {
+		t.Errorf("Expected %d tags, got %d: %v", len(expected), len(metadata), metadata)
+	}
+
+	// The file should still be readable
+	if _, _, err := LoadWav(filename); err != nil {
+		t.Errorf("Failed to load the file with metadata: %v", err)
+	}
+}
+
+func TestReadWavMetadataNoTags(t *testing.T) {
+	metadata, err := ReadWavMetadata("test.wav")
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %v", err)
+	}
+	if metadata == nil {
+		t.Error("Expected an empty map, got nil")
+	}
+}
+
+func TestSaveWavWithMetadata(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "tagged.wav")
+	samples := []int16{100, -100, 200, -200}
+	meta := map[string]string{
+		"title":    "Odd length",
+		"artist":   "Mixorama",
+		"comments": "Mixed with mixorama",
+		"IXYZ":     "Custom",
+	}
+
+	if err := SaveWavWithMetadata(filename, samples, 48000, 2, meta); err != nil {
+		t.Fatalf("Failed to save WAV file with metadata: %v", err)
+	}
+
+	metadata, err := ReadWavMetadata(filename)
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %v", err)
+	}
+	if len(metadata) != len(meta) {
+		t.Errorf("Expected %d tags, got %d: %v", len(meta), len(metadata), metadata)
+	}
+	for key, value := range meta {
+		if metadata[key] != value {
+			t.Errorf("Expected %s to be %q, got %q", key, value, metadata[key])
+		}
+	}
+
+	loaded, sampleRate, err := LoadWav(filename)
+	if err != nil {
+		t.Fatalf("Failed to load WAV file with metadata: %v", err)
+	}
+	if sampleRate != 48000 || len(loaded) != len(samples) {
+		t.Errorf("Expected %d samples at 48000 Hz, got %d samples at %d Hz", len(samples), len(loaded), sampleRate)
+	}
+
+	if err := SaveWavWithMetadata(filename, samples, 48000, 2, map[string]string{"unknown key": "x"}); err == nil {
+		t.Error("Expected error for an unknown tag")
+	}
+}
+
+func TestSaveWavWithMetadataDuplicateKey(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "tagged.wav")
+	meta := map[string]string{
+		"title": "Friendly",
+		"INAM":  "Raw",
+	}
+
+	err := SaveWavWithMetadata(filename, []int16{100, -100}, 48000, 2, meta)
+	if err == nil {
+		t.Fatal("Expected error when a friendly key and its chunk ID are both given")
+	}
+	if !strings.Contains(err.Error(), "title") || !strings.Contains(err.Error(), "INAM") {
+		t.Errorf("Expected the error to name both keys, got: %v", err)
+	}
+	if _, err := os.Stat(filename); err == nil {
+		t.Error("Expected no file to be saved")
+	}
+}
@@ -0,0 +1,298 @@
+package mixorama
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MixMethod selects the algorithm used by Mixer.Mix
+type MixMethod int
+
+const (
+	// MixLinear mixes the tracks with LinearSummation
+	MixLinear MixMethod = iota
+	// MixWeighted mixes the tracks with WeightedSummation, giving each track an equal weight
+	MixWeighted
+	// MixRMS mixes the tracks with RMSMixing
+	MixRMS
+)
+
+// declickMs is the length in milliseconds of the fades that Mix applies to each track when auto-declick is enabled
+const declickMs = 5
+
+// mixChunkSize is the number of samples that Mixer.Mix mixes between each call to the progress callback
+const mixChunkSize = 65536
+
+// Mixer collects tracks with the same sample rate and mixes them together
+type Mixer struct {
+	sampleRate     int
+	tracks         [][]int16
+	trackChannels  []int
+	progress       func(done, total int)
+	outputChannels int
+	bitDepth       int
+	autoDeclick    bool
+}
+
+// NewMixer creates a new Mixer for tracks with the given sample rate
+func NewMixer(sampleRate int) *Mixer {
+	return &Mixer{sampleRate: sampleRate}
+}
+
+// SampleRate returns the sample rate of the mixer
+func (m *Mixer) SampleRate() int {
+	return m.sampleRate
+}
+
+// Tracks returns the tracks that have been added to the mixer
+func (m *Mixer) Tracks() [][]int16 {
+	return m.tracks
+}
+
+// OnProgress registers a callback that is called by Mix each time a chunk of samples has been mixed,
+// with the number of samples that have been mixed so far and the total number of samples.
+// Passing nil removes the callback.
+func (m *Mixer) OnProgress(progress func(done, total int)) {
+	m.progress = progress
+}
+
+// SetOutputFormat makes Mix convert the tracks to numChannels channels before mixing, and quantize the mix
+// to bitDepth bits with Dither. Stereo tracks are downmixed for mono output and mono tracks are duplicated
+// for stereo output. The bit depth must be between 1 and 16, since the mix is returned as int16 samples.
+func (m *Mixer) SetOutputFormat(numChannels, bitDepth int) error {
+	if numChannels < 1 {
+		return errors.New("number of channels must be positive")
+	}
+	if bitDepth < 1 || bitDepth > 16 {
+		return errors.New("bit depth must be between 1 and 16")
+	}
+	m.outputChannels = numChannels
+	m.bitDepth = bitDepth
+	return nil
+}
+
+// SetAutoDeclick makes Mix apply a short linear fade to the start and end of each track before mixing,
+// to avoid clicks from tracks that start or stop abruptly. The tracks that have been added are not modified.
+func (m *Mixer) SetAutoDeclick(enabled bool) {
+	m.autoDeclick = enabled
+}
+
+// AddTrack adds a track to the mixer. The samples are expected to have the same sample rate as the mixer,
+// and to be interleaved stereo, as returned by LoadWav. The sample rate is not checked, since the samples
+// do not carry one. Use AddTrackRate or AddWav to have it checked.
+func (m *Mixer) AddTrack(samples []int16) error {
+	return m.AddTrackChannels(samples, 2)
+}
+
+// AddTrackRate adds a track of interleaved stereo samples to the mixer, like AddTrack,
+// but rejects it if the given sample rate does not match the sample rate of the mixer
+func (m *Mixer) AddTrackRate(samples []int16, sampleRate int) error {
+	if sampleRate != m.sampleRate {
+		return fmt.Errorf("sample rate is %d, but the mixer uses %d", sampleRate, m.sampleRate)
+	}
+	return m.AddTrack(samples)
+}
+
+// AddTrackChannels adds a track with the given number of interleaved channels to the mixer.
+// The samples are expected to have the same sample rate as the mixer.
+func (m *Mixer) AddTrackChannels(samples []int16, numChannels int) error {
+	if len(samples) == 0 {
+		return errors.New("track has no samples")
+	}
+	if numChannels < 1 {
+		return errors.New("number of channels must be positive")
+	}
+	m.tracks = append(m.tracks, samples)
+	m.trackChannels = append(m.trackChannels, numChannels)
+	return nil
+}
+
+// convertChannels converts interleaved samples from one number of channels to another, by averaging all channels
+// when converting to mono and by duplicating the channel when converting from mono
+func convertChannels(samples []int16, from, to int) ([]int16, error) {
+	if from == to {
+		return samples, nil
+	}
+	if len(samples)%from != 0 {
+		return nil, errors.New("sample length must be a multiple of the number of channels")
+	}
+	numFrames := len(samples) / from
+	converted := make([]int16, numFrames*to)
+	switch {
+	case to == 1:
+		for i := 0; i < numFrames; i++ {
+			sum := 0
+			for c := 0; c < from; c++ {
+				sum += int(samples[i*from+c])
+			}
+			converted[i] = int16(sum / from)
+		}
+	case from == 1:
+		for i := 0; i < numFrames; i++ {
+			for c := 0; c < to; c++ {
+				converted[i*to+c] = samples[i]
+			}
+		}
+	default:
+		return nil, fmt.Errorf("can not convert from %d to %d channels", from, to)
+	}
+	return converted, nil
+}
+
+// declick returns a copy of the interleaved samples with a linear fade in over the first numFrames frames
+// and a linear fade out over the last numFrames frames. The fades are shortened to half the track if needed.
+func declick(samples []int16, numChannels, numFrames int) []int16 {
+	totalFrames := len(samples) / numChannels
+	if numFrames > totalFrames/2 {
+		numFrames = totalFrames / 2
+	}
+	declicked := make([]int16, len(samples))
+	copy(declicked, samples)
+	for i := 0; i < numFrames; i++ {
+		gain := FadeLinear.gain(fadePosition(i, numFrames))
+		for c := 0; c < numChannels; c++ {
+			in := i*numChannels + c
+			out := (totalFrames-1-i)*numChannels + c
+			declicked[in] = int16(math.Round(float64(samples[in]) * gain))
+			declicked[out] = int16(math.Round(float64(samples[out]) * gain))
+		}
+	}
+	return declicked
+}
+
+// AddWav loads a .wav file and adds it as a track, rejecting it if the sample rate does not match the mixer
+func (m *Mixer) AddWav(filename string) error {
+	samples, sampleRate, err := LoadWav(filename)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", filename, err)
+	}
+	if err := m.AddTrackRate(samples, sampleRate); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	return nil
+}
+
+// Mix pads all tracks to the length of the longest track and mixes them with the given method.
+// The tracks are mixed in chunks, and the progress callback is called after each chunk, if it is set.
+// If an output format is set, the tracks are converted to its number of channels first,
+// and the mix is quantized to its bit depth.
+func (m *Mixer) Mix(method MixMethod) ([]int16, error) {
+	if len(m.tracks) == 0 {
+		return nil, errors.New("no tracks added")
+	}
+
+	var mix func(samples ...[]int16) ([]int16, error)
+	switch method {
+	case MixLinear:
+		mix = LinearSummation
+	case MixWeighted:
+		weights := make([]float64, len(m.tracks))
+		for i := range weights {
+			weights[i] = 1.0 / float64(len(m.tracks))
+		}
+		mix = func(samples ...[]int16) ([]int16, error) {
+			return WeightedSummation(weights, samples...)
+		}
+	case MixRMS:
+		mix = RMSMixing
+	default:
+		return nil, errors.New("unknown mix method")
+	}
+
+	tracks := m.tracks
+	if m.outputChannels > 0 {
+		tracks = make([][]int16, len(m.tracks))
+		for i, track := range m.tracks {
+			converted, err := convertChannels(track, m.trackChannels[i], m.outputChannels)
+			if err != nil {
+				return nil, fmt.Errorf("track %d: %w", i+1, err)
+			}
+			tracks[i] = converted
+		}
+	} else {
+		for _, numChannels := range m.trackChannels {
+			if numChannels != m.trackChannels[0] {
+				return nil, errors.New("tracks have different numbers of channels, set an output format to convert them")
+			}
+		}
+	}
+
+	if m.autoDeclick {
+		declicked := make([][]int16, len(tracks))
+		for i, track := range tracks {
+			numChannels := m.trackChannels[i]
+			if m.outputChannels > 0 {
+				numChannels = m.outputChannels
+			}
+			declicked[i] = declick(track, numChannels, declickMs*m.sampleRate/1000)
+		}
+		tracks = declicked
+	}
+
+	padded := padToLongest(tracks)
+	total := len(padded[0])
+	combined := make([]int16, total)
+	chunk := make([][]int16, len(padded))
+	for start := 0; start < total; start += mixChunkSize {
+		end := start + mixChunkSize
+		if end > total {
+			end = total
+		}
+		for i, track := range padded {
+			chunk[i] = track[start:end]
+		}
+		mixed, err := mix(chunk...)
+		if err != nil {
+			return nil, err
+		}
+		copy(combined[start:], mixed)
+		if m.progress != nil {
+			m.progress(end, total)
+		}
+	}
+
+	if m.bitDepth > 0 && m.bitDepth < 16 {
+		floatSamples := make([]float64, total)
+		for i, sample := range combined {
+			floatSamples[i] = float64(sample) / float32Scale
+		}
+		combined = Dither(floatSamples, m.bitDepth)
+	}
+
+	return combined, nil
+}
+
+// MixToFile loads the input .wav files, checks that their sample rates match, mixes them with the given method
+// and saves the result as a stereo .wav file. Tracks of different lengths are padded with silence.
+func MixToFile(outputFile string, method MixMethod, inputFiles ...string) error {
+	if len(inputFiles) == 0 {
+		return errors.New("no input files provided")
+	}
+
+	first, sampleRate, err := LoadWav(inputFiles[0])
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", inputFiles[0], err)
+	}
+
+	mixer := NewMixer(sampleRate)
+	if err := mixer.AddTrack(first); err != nil {
+		return fmt.Errorf("%s: %w", inputFiles[0], err)
+	}
+	for _, inputFile := range inputFiles[1:] {
+		if err := mixer.AddWav(inputFile); err != nil {
+			return err
+		}
+	}
+
+	combined, err := mixer.Mix(method)
+	if err != nil {
+		return err
+	}
+
+	// LoadWav always returns stereo samples
+	if err := saveWav(outputFile, combined, sampleRate, 2); err != nil {
+		return fmt.Errorf("failed to save %s: %w", outputFile, err)
+	}
+	return nil
+}
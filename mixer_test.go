@@ -0,0 +1,275 @@
+package mixorama
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMixerDifferentLengths(t *testing.T) {
+	mixer := NewMixer(44100)
+	for _, length := range []int{10, 25, 5} {
+		if err := mixer.AddTrack(createTestWaveform(1000, length)); err != nil {
+			t.Fatalf("Failed to add track: %v", err)
+		}
+	}
+
+	for _, method := range []MixMethod{MixLinear, MixWeighted, MixRMS} {
+		mixed, err := mixer.Mix(method)
+		if err != nil {
+			t.Fatalf("Error in Mix with method %d: %v", method, err)
+		}
+		if len(mixed) != 25 {
+			t.Errorf("Expected the mix to have the length of the longest track (25) with method %d, got %d", method, len(mixed))
+		}
+	}
+
+	// Only the longest track is present at the end of the linear mix
+	mixed, _ := mixer.Mix(MixLinear)
+	if mixed[0] != 3000 || mixed[24] != 1000 {
+		t.Errorf("Unexpected linear mix: %v", mixed)
+	}
+}
+
+func TestMixerErrors(t *testing.T) {
+	mixer := NewMixer(44100)
+	if _, err := mixer.Mix(MixLinear); err == nil {
+		t.Error("Expected error when mixing without tracks")
+	}
+	if err := mixer.AddTrack(nil); err == nil {
+		t.Error("Expected error when adding an empty track")
+	}
+}
+
+func TestMixerOnProgress(t *testing.T) {
+	mixer := NewMixer(44100)
+	total := mixChunkSize*2 + 100
+	for i := 0; i < 2; i++ {
+		if err := mixer.AddTrack(createTestWaveform(1000, total)); err != nil {
+			t.Fatalf("Failed to add track: %v", err)
+		}
+	}
+
+	var calls []int
+	mixer.OnProgress(func(done, n int) {
+		if n != total {
+			t.Errorf("Expected the total to be %d, got %d", total, n)
+		}
+		calls = append(calls, done)
+	})
+	mixed, err := mixer.Mix(MixLinear)
+	if err != nil {
+		t.Fatalf("Error in Mix: %v", err)
+	}
+	if len(mixed) != total || mixed[total-1] != 2000 {
+		t.Errorf("Expected %d mixed samples ending with 2000", total)
+	}
+
+	if len(calls) != 3 {
+		t.Fatalf("Expected the progress callback to be called 3 times, got %d", len(calls))
+	}
+	for i := 1; i < len(calls); i++ {
+		if calls[i] <= calls[i-1] {
+			t.Errorf("Expected the progress to increase, got %v", calls)
+		}
+	}
+	if calls[len(calls)-1] != total {
+		t.Errorf("Expected the last progress call to be %d, got %d", total, calls[len(calls)-1])
+	}
+}
+
+func TestMixerSetOutputFormat(t *testing.T) {
+	mixer := NewMixer(44100)
+	stereo := make([]int16, 200)
+	for i := range stereo {
+		if i%2 == 0 {
+			stereo[i] = 1000 // Left channel
+		} else {
+			stereo[i] = 3000 // Right channel
+		}
+	}
+	if err := mixer.AddTrack(stereo); err != nil {
+		t.Fatalf("Failed to add track: %v", err)
+	}
+	if err := mixer.AddTrackChannels(createTestWaveform(500, 100), 1); err != nil {
+		t.Fatalf("Failed to add track: %v", err)
+	}
+
+	if _, err := mixer.Mix(MixLinear); err == nil {
+		t.Error("Expected error when mixing tracks with different numbers of channels without an output format")
+	}
+
+	// Stereo in, mono out
+	if err := mixer.SetOutputFormat(1, 16); err != nil {
+		t.Fatalf("Failed to set the output format: %v", err)
+	}
+	mono, err := mixer.Mix(MixLinear)
+	if err != nil {
+		t.Fatalf("Error in Mix: %v", err)
+	}
+	if len(mono) != 100 {
+		t.Fatalf("Expected 100 downmixed samples, got %d", len(mono))
+	}
+	for i, v := range mono {
+		if v != 2500 {
+			t.Fatalf("Expected sample %d to be 2500 (the average of 1000 and 3000, plus 500), got %d", i, v)
+		}
+	}
+
+	// Mono in, stereo out
+	if err := mixer.SetOutputFormat(2, 16); err != nil {
+		t.Fatalf("Failed to set the output format: %v", err)
+	}
+	stereoMix, err := mixer.Mix(MixLinear)
+	if err != nil {
+		t.Fatalf("Error in Mix: %v", err)
+	}
+	if len(stereoMix) != 200 || stereoMix[0] != 1500 || stereoMix[1] != 3500 {
+		t.Errorf("Expected 200 stereo samples starting with 1500 and 3500, got %d samples starting with %v", len(stereoMix), stereoMix[:2])
+	}
+
+	// Lower bit depth
+	if err := mixer.SetOutputFormat(1, 8); err != nil {
+		t.Fatalf("Failed to set the output format: %v", err)
+	}
+	quantized, err := mixer.Mix(MixLinear)
+	if err != nil {
+		t.Fatalf("Error in Mix: %v", err)
+	}
+	for i, v := range quantized {
+		if v%256 != 0 {
+			t.Fatalf("Expected the mix to be quantized to 8 bits, got %d at index %d", v, i)
+		}
+	}
+
+	if err := mixer.SetOutputFormat(2, 24); err == nil {
+		t.Error("Expected error for a bit depth above 16")
+	}
+}
+
+func TestMixerSetAutoDeclick(t *testing.T) {
+	// One second of full amplitude mono samples, at 8000 Hz the fades are 40 frames long
+	track := createTestWaveform(math.MaxInt16, 8000)
+
+	mixer := NewMixer(8000)
+	if err := mixer.AddTrackChannels(track, 1); err != nil {
+		t.Fatalf("Failed to add track: %v", err)
+	}
+	mixer.SetAutoDeclick(true)
+
+	mixed, err := mixer.Mix(MixLinear)
+	if err != nil {
+		t.Fatalf("Error in Mix: %v", err)
+	}
+	if mixed[0] != 0 {
+		t.Errorf("Expected the first sample to be 0, got %d", mixed[0])
+	}
+	for i := 1; i < 40; i++ {
+		if mixed[i] <= mixed[i-1] {
+			t.Fatalf("Expected the samples to ramp up, got %d after %d at index %d", mixed[i], mixed[i-1], i)
+		}
+	}
+	if mixed[40] != math.MaxInt16 {
+		t.Errorf("Expected full amplitude after the fade, got %d", mixed[40])
+	}
+	if last := mixed[len(mixed)-1]; last != 0 {
+		t.Errorf("Expected the last sample to be 0, got %d", last)
+	}
+	if track[0] != math.MaxInt16 {
+		t.Error("Expected the added track to be left unmodified")
+	}
+
+	mixer.SetAutoDeclick(false)
+	mixed, err = mixer.Mix(MixLinear)
+	if err != nil {
+		t.Fatalf("Error in Mix: %v", err)
+	}
+	if mixed[0] != math.MaxInt16 {
+		t.Errorf("Expected no fade when auto-declick is disabled, got %d", mixed[0])
+	}
+}
+
+func TestMixerAddTrackRate(t *testing.T) {
+	mixer := NewMixer(44100)
+	if err := mixer.AddTrackRate(createTestWaveform(1000, 100), 22050); err == nil {
+		t.Error("Expected error when adding a track with a mismatched sample rate")
+	}
+	if len(mixer.Tracks()) != 0 {
+		t.Errorf("Expected the mismatched track to be rejected, got %d tracks", len(mixer.Tracks()))
+	}
+	if err := mixer.AddTrackRate(createTestWaveform(1000, 100), 44100); err != nil {
+		t.Errorf("Failed to add a track with a matching sample rate: %v", err)
+	}
+}
+
+func TestMixerAddWavSampleRateMismatch(t *testing.T) {
+	filename := "test_mixer.wav"
+	defer os.Remove(filename) // Cleanup after test
+
+	if err := SaveWav(filename, []int16{1000, -1000}, 22050); err != nil {
+		t.Fatalf("Failed to save WAV file: %v", err)
+	}
+
+	mixer := NewMixer(44100)
+	if err := mixer.AddWav(filename); err == nil {
+		t.Error("Expected error when adding a track with a mismatched sample rate")
+	}
+
+	mixer = NewMixer(22050)
+	if err := mixer.AddWav(filename); err != nil {
+		t.Errorf("Failed to add a track with a matching sample rate: %v", err)
+	}
+}
+
+func TestMixToFile(t *testing.T) {
+	dir := t.TempDir()
+	input1 := filepath.Join(dir, "input1.wav")
+	input2 := filepath.Join(dir, "input2.wav")
+	output := filepath.Join(dir, "output.wav")
+
+	if err := SaveWav(input1, createTestWaveform(1000, 100), 44100); err != nil {
+		t.Fatalf("Failed to save WAV file: %v", err)
+	}
+	if err := SaveWav(input2, createTestWaveform(2000, 50), 44100); err != nil {
+		t.Fatalf("Failed to save WAV file: %v", err)
+	}
+
+	if err := MixToFile(output, MixLinear, input1, input2); err != nil {
+		t.Fatalf("Error in MixToFile: %v", err)
+	}
+
+	mixed, sampleRate, err := LoadWav(output)
+	if err != nil {
+		t.Fatalf("Failed to load the mixed file: %v", err)
+	}
+	if sampleRate != 44100 {
+		t.Errorf("Expected sample rate 44100, got %d", sampleRate)
+	}
+	// The mono inputs are loaded as stereo, and the longest input has 100 samples
+	if len(mixed) != 200 {
+		t.Fatalf("Expected 200 samples, got %d", len(mixed))
+	}
+	if mixed[0] != 3000 || mixed[199] != 1000 {
+		t.Errorf("Unexpected mixed samples: first %d, last %d", mixed[0], mixed[199])
+	}
+}
+
+func TestMixToFileErrorNamesFile(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.wav")
+	missing := filepath.Join(dir, "missing.wav")
+
+	if err := SaveWav(input, createTestWaveform(1000, 100), 44100); err != nil {
+		t.Fatalf("Failed to save WAV file: %v", err)
+	}
+
+	err := MixToFile(filepath.Join(dir, "output.wav"), MixLinear, input, missing)
+	if err == nil {
+		t.Fatal("Expected error for a missing input file")
+	}
+	if !strings.Contains(err.Error(), missing) {
+		t.Errorf("Expected the error to name the missing file, got: %v", err)
+	}
+}
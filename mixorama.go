@@ -7,20 +7,32 @@ import (
 
 // LinearSummation mixes multiple audio samples by adding them together.
 // It automatically clamps the sum to avoid overflow and distortion.
+// Large mixes are split into ranges that are mixed in parallel.
 func LinearSummation(samples ...[]int16) ([]int16, error) {
 	if len(samples) == 0 {
 		return nil, errors.New("no samples provided")
 	}
 
 	numSamples := len(samples[0])
+	for _, sample := range samples {
+		if len(sample) != numSamples {
+			return nil, errors.New("mismatched sample lengths")
+		}
+	}
+
 	combined := make([]int16, numSamples)
+	inParallel(numSamples, len(samples), func(start, end int) {
+		linearSummationRange(samples, combined, start, end)
+	})
 
-	for i := 0; i < numSamples; i++ {
+	return combined, nil
+}
+
+// linearSummationRange adds the samples from start to end together and stores the clamped sums in combined
+func linearSummationRange(samples [][]int16, combined []int16, start, end int) {
+	for i := start; i < end; i++ {
 		sum := int32(0)
 		for _, sample := range samples {
-			if len(sample) != numSamples {
-				return nil, errors.New("mismatched sample lengths")
-			}
 			sum += int32(sample[i])
 		}
 		// Clamp the result to avoid overflow
@@ -31,10 +43,176 @@ func LinearSummation(samples ...[]int16) ([]int16, error) {
 		}
 		combined[i] = int16(sum)
 	}
+}
+
+// LinearSummationPadded works like LinearSummation, but pads all samples with zeros (silence)
+// to the length of the longest one, instead of returning an error for mismatched lengths.
+func LinearSummationPadded(samples ...[]int16) ([]int16, error) {
+	return LinearSummation(padToLongest(samples)...)
+}
+
+// SumToInt32 adds the samples together without clamping, and returns the sums as int32, so that callers can
+// apply their own normalization or limiting. The sums can not overflow for fewer than 65536 tracks.
+func SumToInt32(samples ...[]int16) ([]int32, error) {
+	if len(samples) == 0 {
+		return nil, errors.New("no samples provided")
+	}
+
+	numSamples := len(samples[0])
+	for _, sample := range samples {
+		if len(sample) != numSamples {
+			return nil, errors.New("mismatched sample lengths")
+		}
+	}
+
+	sums := make([]int32, numSamples)
+	for _, sample := range samples {
+		for i, v := range sample {
+			sums[i] += int32(v)
+		}
+	}
+
+	return sums, nil
+}
+
+// LinearSummationAutoScale mixes multiple audio samples by adding them together, like LinearSummation,
+// but instead of clamping each sample, the whole mix is scaled down by a single factor if the sum would clip.
+// The peak of a scaled mix is one step below full scale, so that it is not counted by CountClippedSamples.
+func LinearSummationAutoScale(samples ...[]int16) ([]int16, error) {
+	sums, err := SumToInt32(samples...)
+	if err != nil {
+		return nil, err
+	}
+
+	peak := int32(0)
+	for _, sum := range sums {
+		if sum > peak {
+			peak = sum
+		} else if -sum > peak {
+			peak = -sum
+		}
+	}
+
+	combined := make([]int16, len(sums))
+	if peak < math.MaxInt16 {
+		for i, sum := range sums {
+			combined[i] = int16(sum)
+		}
+		return combined, nil
+	}
+
+	scale := float64(math.MaxInt16-1) / float64(peak)
+	for i, sum := range sums {
+		combined[i] = int16(math.Round(float64(sum) * scale))
+	}
 
 	return combined, nil
 }
 
+// OverflowStrategy selects what LinearSummationWith does when the sum does not fit in an int16
+type OverflowStrategy int
+
+const (
+	// OverflowClamp clamps each sample to the int16 range, like LinearSummation
+	OverflowClamp OverflowStrategy = iota
+	// OverflowWrap keeps the lowest 16 bits of the sum, so that overflowing samples wrap around
+	OverflowWrap
+	// OverflowScale scales the whole mix down so that no samples clip, like LinearSummationAutoScale
+	OverflowScale
+)
+
+// LinearSummationWith mixes multiple audio samples by adding them together,
+// using the given strategy for sums that do not fit in an int16
+func LinearSummationWith(strategy OverflowStrategy, samples ...[]int16) ([]int16, error) {
+	switch strategy {
+	case OverflowClamp:
+		return LinearSummation(samples...)
+	case OverflowScale:
+		return LinearSummationAutoScale(samples...)
+	case OverflowWrap:
+		sums, err := SumToInt32(samples...)
+		if err != nil {
+			return nil, err
+		}
+		combined := make([]int16, len(sums))
+		for i, sum := range sums {
+			combined[i] = int16(sum)
+		}
+		return combined, nil
+	}
+	return nil, errors.New("unknown overflow strategy")
+}
+
+// MixWithOffsets mixes multiple audio samples by adding them together, like LinearSummation, but first places
+// each of them at the corresponding sample offset by padding the front with zeros (silence).
+// The result has the length of the track that ends last.
+func MixWithOffsets(offsets []int, samples ...[]int16) ([]int16, error) {
+	if len(offsets) != len(samples) {
+		return nil, errors.New("number of offsets must match number of samples")
+	}
+
+	shifted := make([][]int16, len(samples))
+	for i, sample := range samples {
+		if offsets[i] < 0 {
+			return nil, errors.New("offsets can not be negative")
+		}
+		if offsets[i] == 0 {
+			shifted[i] = sample
+			continue
+		}
+		shifted[i] = make([]int16, offsets[i]+len(sample))
+		copy(shifted[i][offsets[i]:], sample)
+	}
+
+	return LinearSummationPadded(shifted...)
+}
+
+// MixAtTimes works like MixWithOffsets, but places each mono track at a start time in seconds instead of at
+// a sample offset. The start times are rounded to the nearest sample.
+func MixAtTimes(sampleRate int, startTimes []float64, tracks ...[]int16) ([]int16, error) {
+	return MixAtTimesChannels(sampleRate, 1, startTimes, tracks...)
+}
+
+// MixAtTimesChannels works like MixAtTimes, but for interleaved tracks with the given number of channels,
+// so that each track starts at the beginning of a frame
+func MixAtTimesChannels(sampleRate, numChannels int, startTimes []float64, tracks ...[]int16) ([]int16, error) {
+	if sampleRate <= 0 || numChannels <= 0 {
+		return nil, errors.New("sample rate and number of channels must be positive")
+	}
+	if len(startTimes) != len(tracks) {
+		return nil, errors.New("number of start times must match number of tracks")
+	}
+
+	offsets := make([]int, len(startTimes))
+	for i, startTime := range startTimes {
+		offsets[i] = int(math.Round(startTime*float64(sampleRate))) * numChannels
+	}
+
+	return MixWithOffsets(offsets, tracks...)
+}
+
+// padToLongest pads all samples with zeros to the length of the longest one.
+// Samples that already have that length are not copied.
+func padToLongest(samples [][]int16) [][]int16 {
+	longest := 0
+	for _, sample := range samples {
+		if len(sample) > longest {
+			longest = len(sample)
+		}
+	}
+
+	padded := make([][]int16, len(samples))
+	for i, sample := range samples {
+		if len(sample) == longest {
+			padded[i] = sample
+			continue
+		}
+		padded[i] = make([]int16, longest)
+		copy(padded[i], sample)
+	}
+	return padded
+}
+
 // WeightedSummation mixes multiple audio samples by applying a weight to each sample.
 // Each sample's amplitude is scaled by its corresponding weight before summing.
 func WeightedSummation(weights []float64, samples ...[]int16) ([]int16, error) {
@@ -69,26 +247,77 @@ func WeightedSummation(weights []float64, samples ...[]int16) ([]int16, error) {
 	return combined, nil
 }
 
+// WeightedSummationNormalized works like WeightedSummation, but first scales the weights so that
+// their absolute values sum to 1.0, which prevents clipping regardless of the given weights.
+func WeightedSummationNormalized(weights []float64, samples ...[]int16) ([]int16, error) {
+	sum := float64(0)
+	for _, weight := range weights {
+		sum += math.Abs(weight)
+	}
+	if sum == 0 {
+		return nil, errors.New("weights can not all be zero")
+	}
+
+	normalizedWeights := make([]float64, len(weights))
+	for i, weight := range weights {
+		normalizedWeights[i] = weight / sum
+	}
+
+	return WeightedSummation(normalizedWeights, samples...)
+}
+
 // RMSMixing correctly mixes audio samples using the Root Mean Square method.
+// The magnitude of each mixed sample is the RMS of the input samples, while the sign is
+// taken from the linear sum, so that the mixed signal still oscillates around zero.
+// Since the squares are averaged, mixing N identical tracks gives the same amplitude as a single track.
+// Use RMSMixingGain to make the mix louder. Large mixes are split into ranges that are mixed in parallel.
 func RMSMixing(samples ...[]int16) ([]int16, error) {
+	return RMSMixingGain(1, samples...)
+}
+
+// RMSMixingGain works like RMSMixing, but multiplies each mixed sample by gain before it is clamped.
+// A gain of math.Sqrt(float64(len(samples))) gives the square root of the sum of the squares instead of the mean,
+// so that mixing N identical tracks gives sqrt(N) times the amplitude of a single track, which is N times the power.
+func RMSMixingGain(gain float64, samples ...[]int16) ([]int16, error) {
 	if len(samples) == 0 {
 		return nil, errors.New("no samples provided")
 	}
 
 	numSamples := len(samples[0])
+	for _, sample := range samples {
+		if len(sample) != numSamples {
+			return nil, errors.New("mismatched sample lengths")
+		}
+	}
+
 	combined := make([]int16, numSamples)
+	inParallel(numSamples, len(samples), func(start, end int) {
+		rmsMixingRange(samples, combined, start, end, gain)
+	})
 
-	for i := 0; i < numSamples; i++ {
+	return combined, nil
+}
+
+// rmsMixingRange mixes the samples from start to end with the RMS method, multiplies the result by gain
+// and stores it in combined
+func rmsMixingRange(samples [][]int16, combined []int16, start, end int, gain float64) {
+	for i := start; i < end; i++ {
+		sum := float64(0)
 		sumSquares := float64(0)
 		for _, sample := range samples {
-			if len(sample) != numSamples {
-				return nil, errors.New("mismatched sample lengths")
-			}
+			sum += float64(sample[i])
 			// Square the sample value and accumulate
 			sumSquares += float64(sample[i]) * float64(sample[i])
 		}
 		// Calculate RMS by taking the square root of the mean of squares
-		rms := math.Sqrt(sumSquares / float64(len(samples)))
+		rms := math.Sqrt(sumSquares/float64(len(samples))) * gain
+
+		// Apply the sign of the linear sum, so that negative signals stay negative
+		if sum < 0 {
+			rms = -rms
+		} else if sum == 0 {
+			rms = 0
+		}
 
 		// Clamp the result to int16 range
 		if rms > float64(math.MaxInt16) {
@@ -98,6 +327,60 @@ func RMSMixing(samples ...[]int16) ([]int16, error) {
 		}
 		combined[i] = int16(rms)
 	}
+}
 
-	return combined, nil
+// mixPerChannel splits interleaved samples into channels, mixes each channel separately with the given function
+// and interleaves the result again
+func mixPerChannel(numChannels int, samples [][]int16, mix func(...[]int16) ([]int16, error)) ([]int16, error) {
+	if numChannels <= 0 {
+		return nil, errors.New("number of channels must be positive")
+	}
+	if len(samples) == 0 {
+		return nil, errors.New("no samples provided")
+	}
+
+	// Collect the tracks for each channel
+	channelTracks := make([][][]int16, numChannels)
+	for _, sample := range samples {
+		if len(sample)%numChannels != 0 {
+			return nil, errors.New("sample length is not a multiple of the number of channels")
+		}
+		for c, channel := range SplitChannels(sample, numChannels) {
+			channelTracks[c] = append(channelTracks[c], channel)
+		}
+	}
+
+	mixedChannels := make([][]int16, numChannels)
+	for c, tracks := range channelTracks {
+		mixed, err := mix(tracks...)
+		if err != nil {
+			return nil, err
+		}
+		mixedChannels[c] = mixed
+	}
+
+	return MergeChannels(mixedChannels)
+}
+
+// LinearSummationStereo works like LinearSummation, but for interleaved samples with the given number of channels.
+// Each channel is mixed separately, so that the left and right channels are kept apart.
+func LinearSummationStereo(numChannels int, samples ...[]int16) ([]int16, error) {
+	return mixPerChannel(numChannels, samples, LinearSummation)
+}
+
+// WeightedSummationStereo works like WeightedSummation, but for interleaved samples with the given number of channels.
+// Each channel is mixed separately, so that the left and right channels are kept apart.
+func WeightedSummationStereo(numChannels int, weights []float64, samples ...[]int16) ([]int16, error) {
+	if len(weights) != len(samples) {
+		return nil, errors.New("number of weights must match number of samples")
+	}
+	return mixPerChannel(numChannels, samples, func(channelSamples ...[]int16) ([]int16, error) {
+		return WeightedSummation(weights, channelSamples...)
+	})
+}
+
+// RMSMixingStereo works like RMSMixing, but for interleaved samples with the given number of channels.
+// Each channel is mixed separately, so that the left and right channels are kept apart.
+func RMSMixingStereo(numChannels int, samples ...[]int16) ([]int16, error) {
+	return mixPerChannel(numChannels, samples, RMSMixing)
 }
@@ -32,6 +32,195 @@ func TestLinearSummation(t *testing.T) {
 	}
 }
 
+// TestLinearSummationPadded checks that samples of different lengths are padded before mixing
+func TestLinearSummationPadded(t *testing.T) {
+	wave1 := createTestWaveform(1000, 10)
+	wave2 := createTestWaveform(2000, 5)
+
+	result, err := LinearSummationPadded(wave1, wave2)
+	if err != nil {
+		t.Fatalf("Error in LinearSummationPadded: %v", err)
+	}
+	if len(result) != 10 {
+		t.Fatalf("Expected the result to have length 10, got %d", len(result))
+	}
+
+	for i, v := range result {
+		expected := int16(3000)
+		if i >= 5 {
+			expected = 1000 // Only the longer track is present in the tail
+		}
+		if v != expected {
+			t.Errorf("LinearSummationPadded failed at index %d: expected %d, got %d", i, expected, v)
+		}
+	}
+}
+
+func TestLinearSummationAutoScale(t *testing.T) {
+	wave1 := createSineWave(441, 20000, 44100, 1000)
+	wave2 := createSineWave(441, 15000, 44100, 1000)
+	wave3 := createTestWaveform(5000, 1000)
+
+	result, err := LinearSummationAutoScale(wave1, wave2, wave3)
+	if err != nil {
+		t.Fatalf("Error in LinearSummationAutoScale: %v", err)
+	}
+	if clipped := CountClippedSamples(result); clipped != 0 {
+		t.Errorf("Expected no clipped samples, got %d", clipped)
+	}
+
+	// The mix should be the exact sum, scaled by a single factor
+	scale := float64(math.MaxInt16-1) / 40000
+	for i, v := range result {
+		expected := (float64(wave1[i]) + float64(wave2[i]) + float64(wave3[i])) * scale
+		if math.Abs(float64(v)-expected) > 1 {
+			t.Fatalf("Expected sample %d to be about %.0f, got %d", i, expected, v)
+		}
+	}
+
+	// Mixes that do not clip are left unchanged
+	result, err = LinearSummationAutoScale(createTestWaveform(1000, 10), createTestWaveform(2000, 10))
+	if err != nil {
+		t.Fatalf("Error in LinearSummationAutoScale: %v", err)
+	}
+	for i, v := range result {
+		if v != 3000 {
+			t.Errorf("Expected sample %d to be 3000, got %d", i, v)
+		}
+	}
+}
+
+func TestSumToInt32(t *testing.T) {
+	wave1 := []int16{30000, -30000, 1000}
+	wave2 := []int16{20000, -20000, 2000}
+
+	sums, err := SumToInt32(wave1, wave2)
+	if err != nil {
+		t.Fatalf("Error in SumToInt32: %v", err)
+	}
+	expected := []int32{50000, -50000, 3000}
+	if len(sums) != len(expected) {
+		t.Fatalf("Expected %d sums, got %d", len(expected), len(sums))
+	}
+	for i, v := range expected {
+		if sums[i] != v {
+			t.Errorf("Expected the unclamped sum %d at index %d, got %d", v, i, sums[i])
+		}
+	}
+
+	if _, err := SumToInt32(wave1, wave2[:2]); err == nil {
+		t.Error("Expected error for mismatched sample lengths")
+	}
+	if _, err := SumToInt32(); err == nil {
+		t.Error("Expected error when no samples are provided")
+	}
+}
+
+func TestLinearSummationWith(t *testing.T) {
+	wave1 := []int16{30000, -30000, 1000}
+	wave2 := []int16{10000, -10000, 2000}
+
+	clamped, err := LinearSummationWith(OverflowClamp, wave1, wave2)
+	if err != nil {
+		t.Fatalf("Error in LinearSummationWith: %v", err)
+	}
+	if clamped[0] != math.MaxInt16 || clamped[1] != math.MinInt16 || clamped[2] != 3000 {
+		t.Errorf("Expected clamping to give [%d %d 3000], got %v", math.MaxInt16, math.MinInt16, clamped)
+	}
+
+	wrapped, err := LinearSummationWith(OverflowWrap, wave1, wave2)
+	if err != nil {
+		t.Fatalf("Error in LinearSummationWith: %v", err)
+	}
+	// 40000 wraps around to 40000 - 65536
+	if wrapped[0] != 40000-65536 || wrapped[1] != -40000+65536 || wrapped[2] != 3000 {
+		t.Errorf("Expected wrapping to give [%d %d 3000], got %v", 40000-65536, -40000+65536, wrapped)
+	}
+
+	scaled, err := LinearSummationWith(OverflowScale, wave1, wave2)
+	if err != nil {
+		t.Fatalf("Error in LinearSummationWith: %v", err)
+	}
+	if clipped := CountClippedSamples(scaled); clipped != 0 {
+		t.Errorf("Expected scaling to avoid clipping, got %d clipped samples: %v", clipped, scaled)
+	}
+
+	if _, err := LinearSummationWith(OverflowStrategy(42), wave1, wave2); err == nil {
+		t.Error("Expected error for an unknown overflow strategy")
+	}
+}
+
+func TestMixWithOffsets(t *testing.T) {
+	track := createTestWaveform(1000, 500)
+	clip := createTestWaveform(2000, 50)
+
+	result, err := MixWithOffsets([]int{0, 100}, track, clip)
+	if err != nil {
+		t.Fatalf("Error in MixWithOffsets: %v", err)
+	}
+	if len(result) != 500 {
+		t.Fatalf("Expected the result to have length 500, got %d", len(result))
+	}
+	for i, v := range result {
+		expected := int16(1000)
+		if i >= 100 && i < 150 {
+			expected = 3000 // The clip is placed at offset 100
+		}
+		if v != expected {
+			t.Errorf("MixWithOffsets failed at index %d: expected %d, got %d", i, expected, v)
+		}
+	}
+
+	// A clip that ends after the track extends the result
+	result, err = MixWithOffsets([]int{0, 480}, track, clip)
+	if err != nil {
+		t.Fatalf("Error in MixWithOffsets: %v", err)
+	}
+	if len(result) != 530 {
+		t.Errorf("Expected the result to have length 530, got %d", len(result))
+	}
+
+	if _, err := MixWithOffsets([]int{-1, 0}, track, clip); err == nil {
+		t.Error("Expected error for a negative offset")
+	}
+	if _, err := MixWithOffsets([]int{0}, track, clip); err == nil {
+		t.Error("Expected error for mismatched number of offsets")
+	}
+}
+
+func TestMixAtTimes(t *testing.T) {
+	sampleRate := 44100
+	background := createTestWaveform(1000, sampleRate)
+	clip := createTestWaveform(2000, 100)
+
+	mixed, err := MixAtTimes(sampleRate, []float64{0, 0.5}, background, clip)
+	if err != nil {
+		t.Fatalf("Error in MixAtTimes: %v", err)
+	}
+	if len(mixed) != sampleRate {
+		t.Fatalf("Expected %d samples, got %d", sampleRate, len(mixed))
+	}
+	if mixed[22049] != 1000 || mixed[22050] != 3000 || mixed[22149] != 3000 || mixed[22150] != 1000 {
+		t.Errorf("Expected the clip to start at sample 22050, got %v around it", mixed[22048:22052])
+	}
+
+	// Stereo offsets are whole frames
+	stereo, err := MixAtTimesChannels(1000, 2, []float64{0.0015}, []int16{1, 2})
+	if err != nil {
+		t.Fatalf("Error in MixAtTimesChannels: %v", err)
+	}
+	if len(stereo) != 6 || stereo[4] != 1 || stereo[5] != 2 {
+		t.Errorf("Expected the stereo clip to start at frame 2, got %v", stereo)
+	}
+
+	if _, err := MixAtTimes(sampleRate, []float64{0}, background, clip); err == nil {
+		t.Error("Expected error when the number of start times does not match the number of tracks")
+	}
+	if _, err := MixAtTimes(sampleRate, []float64{-1}, clip); err == nil {
+		t.Error("Expected error for a negative start time")
+	}
+}
+
 // TestWeightedSummation checks if the weighted summation mixing works as expected
 func TestWeightedSummation(t *testing.T) {
 	wave1 := createTestWaveform(1000, 10)
@@ -51,6 +240,29 @@ func TestWeightedSummation(t *testing.T) {
 	}
 }
 
+// TestWeightedSummationNormalized checks that the weights are normalized to avoid clipping
+func TestWeightedSummationNormalized(t *testing.T) {
+	wave1 := createTestWaveform(20000, 10)
+	wave2 := createTestWaveform(30000, 10)
+	weights := []float64{2, 2}
+	expected := createTestWaveform(25000, 10) // The average of the two inputs
+
+	result, err := WeightedSummationNormalized(weights, wave1, wave2)
+	if err != nil {
+		t.Fatalf("Error in WeightedSummationNormalized: %v", err)
+	}
+
+	for i, v := range result {
+		if v != expected[i] {
+			t.Errorf("WeightedSummationNormalized failed at index %d: expected %d, got %d", i, expected[i], v)
+		}
+	}
+
+	if _, err := WeightedSummationNormalized([]float64{0, 0}, wave1, wave2); err == nil {
+		t.Error("Expected error for weights that are all zero")
+	}
+}
+
 // TestRMSMixing checks if the RMS mixing works as expected
 func TestRMSMixing(t *testing.T) {
 	wave1 := createTestWaveform(1000, 10)
@@ -71,6 +283,111 @@ func TestRMSMixing(t *testing.T) {
 	}
 }
 
+// TestRMSMixingIdenticalTracks checks the documented behavior when mixing identical tracks
+func TestRMSMixingIdenticalTracks(t *testing.T) {
+	wave := createTestWaveform(1000, 10)
+
+	// The mean of the squares is the same as for a single track
+	result, err := RMSMixing(wave, wave)
+	if err != nil {
+		t.Fatalf("Error in RMSMixing: %v", err)
+	}
+	for i, v := range result {
+		if v != 1000 {
+			t.Errorf("Expected 1000 at index %d, got %d", i, v)
+		}
+	}
+
+	// A gain of sqrt(N) gives the square root of the sum of the squares
+	result, err = RMSMixingGain(math.Sqrt2, wave, wave)
+	if err != nil {
+		t.Fatalf("Error in RMSMixingGain: %v", err)
+	}
+	for i, v := range result {
+		if v != 1414 {
+			t.Errorf("Expected 1414 at index %d, got %d", i, v)
+		}
+	}
+
+	// The gain is applied before clamping
+	result, err = RMSMixingGain(100, wave, wave)
+	if err != nil {
+		t.Fatalf("Error in RMSMixingGain: %v", err)
+	}
+	if result[0] != math.MaxInt16 {
+		t.Errorf("Expected the mix to be clamped to %d, got %d", math.MaxInt16, result[0])
+	}
+}
+
+// TestRMSMixingPreservesSign checks that RMS mixing of out-of-phase signals is not rectified
+func TestRMSMixingPreservesSign(t *testing.T) {
+	wave1 := make([]int16, 1000)
+	wave2 := make([]int16, 1000)
+	for i := range wave1 {
+		phase := 2 * math.Pi * float64(i) / 100
+		wave1[i] = int16(10000 * math.Sin(phase))
+		wave2[i] = int16(-5000 * math.Sin(phase)) // Out of phase with wave1
+	}
+
+	result, err := RMSMixing(wave1, wave2)
+	if err != nil {
+		t.Fatalf("Error in RMSMixing: %v", err)
+	}
+
+	positive, negative := 0, 0
+	sum := 0.0
+	for _, v := range result {
+		if v > 0 {
+			positive++
+		} else if v < 0 {
+			negative++
+		}
+		sum += float64(v)
+	}
+	if positive == 0 || negative == 0 {
+		t.Fatalf("Expected the output to oscillate around zero, got %d positive and %d negative samples", positive, negative)
+	}
+	if mean := sum / float64(len(result)); math.Abs(mean) > 100 {
+		t.Errorf("Expected the output to be centered around zero, got a mean of %.2f", mean)
+	}
+}
+
+// TestStereoMixing checks that the stereo mixing functions keep the left and right channels apart
+func TestStereoMixing(t *testing.T) {
+	// Interleaved stereo, with different content in the left and right channels
+	wave1 := []int16{1000, -1000, 1000, -1000}
+	wave2 := []int16{2000, 500, 2000, 500}
+
+	linear, err := LinearSummationStereo(2, wave1, wave2)
+	if err != nil {
+		t.Fatalf("Error in LinearSummationStereo: %v", err)
+	}
+	weighted, err := WeightedSummationStereo(2, []float64{0.5, 0.5}, wave1, wave2)
+	if err != nil {
+		t.Fatalf("Error in WeightedSummationStereo: %v", err)
+	}
+	rms, err := RMSMixingStereo(2, wave1, wave2)
+	if err != nil {
+		t.Fatalf("Error in RMSMixingStereo: %v", err)
+	}
+
+	for i := 0; i < len(wave1); i += 2 {
+		if linear[i] != 3000 || linear[i+1] != -500 {
+			t.Errorf("Expected left 3000 and right -500 at frame %d, got %d and %d", i/2, linear[i], linear[i+1])
+		}
+		if weighted[i] != 1500 || weighted[i+1] != -250 {
+			t.Errorf("Expected left 1500 and right -250 at frame %d, got %d and %d", i/2, weighted[i], weighted[i+1])
+		}
+		if rms[i] <= 0 || rms[i+1] >= 0 {
+			t.Errorf("Expected a positive left and a negative right channel at frame %d, got %d and %d", i/2, rms[i], rms[i+1])
+		}
+	}
+
+	if _, err := LinearSummationStereo(2, []int16{1, 2, 3}); err == nil {
+		t.Error("Expected error for samples that are not a multiple of the number of channels")
+	}
+}
+
 // TestErrorCases tests that the functions handle error cases correctly
 func TestErrorCases(t *testing.T) {
 	// Mismatched sample lengths
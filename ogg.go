@@ -0,0 +1,36 @@
+package mixorama
+
+import (
+	"os"
+
+	"github.com/jfreymuth/oggvorbis"
+)
+
+// LoadOgg loads a .ogg (Ogg Vorbis) file and returns its samples as []int16 (stereo) along with the sample rate.
+// If the file is mono, it converts it to stereo by duplicating the mono channel to both the left and right channels.
+func LoadOgg(filename string) ([]int16, int, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	floatSamples, format, err := oggvorbis.ReadAll(f)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	samples := ToInt16(floatSamples)
+	if format.Channels == 1 {
+		// Convert mono to stereo by duplicating the mono channel
+		l := len(samples)
+		stereoSamples := make([]int16, l*2)
+		for i := 0; i < l; i++ {
+			stereoSamples[2*i] = samples[i]
+			stereoSamples[2*i+1] = samples[i]
+		}
+		return stereoSamples, format.SampleRate, nil
+	}
+
+	return samples, format.SampleRate, nil
+}
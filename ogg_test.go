@@ -0,0 +1,24 @@
+package mixorama
+
+import "testing"
+
+func TestLoadOgg(t *testing.T) {
+	samples, sampleRate, err := LoadOgg("test.ogg")
+	if err != nil {
+		t.Fatalf("Failed to load test.ogg: %v", err)
+	}
+	if sampleRate != 44100 {
+		t.Errorf("Expected sample rate 44100, got %d", sampleRate)
+	}
+	// test.ogg has 22050 stereo frames
+	if len(samples) != 22050*2 {
+		t.Errorf("Expected %d samples, got %d", 22050*2, len(samples))
+	}
+	if FindPeakAmplitude(samples) == 0 {
+		t.Error("Expected the samples to not be silent")
+	}
+
+	if _, _, err := LoadAudio("test.ogg"); err != nil {
+		t.Errorf("Failed to load test.ogg with LoadAudio: %v", err)
+	}
+}
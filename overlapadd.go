@@ -0,0 +1,117 @@
+package mixorama
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// FrameFunc processes a single windowed frame and returns a frame of the same length.
+// When ProcessOverlapAdd runs with more than one worker, it may be called concurrently.
+type FrameFunc func(frame []float64) []float64
+
+// hannWindow returns a periodic Hann window of the given size
+func hannWindow(size int) []float64 {
+	window := make([]float64, size)
+	for i := 0; i < size; i++ {
+		window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(size))
+	}
+	return window
+}
+
+// ProcessOverlapAdd splits the samples into overlapping Hann-windowed frames, passes each frame to process
+// and reconstructs the signal by overlap-adding the processed frames. If process returns the frame unchanged,
+// the output equals the input. workers controls how many goroutines process frames: 0 uses GOMAXPROCS and
+// 1 processes all frames serially. The recombination is always done in frame order, so the output does not
+// depend on the number of workers.
+func ProcessOverlapAdd(samples []int16, frameSize, hopSize, workers int, process FrameFunc) ([]int16, error) {
+	if frameSize <= 0 {
+		return nil, errors.New("frame size must be positive")
+	}
+	if hopSize <= 0 || hopSize > frameSize/2 {
+		return nil, errors.New("hop size must be positive and at most half the frame size")
+	}
+	if workers < 0 {
+		return nil, errors.New("number of workers can not be negative")
+	}
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	l := len(samples)
+
+	// Pad both ends by a full frame so that every input sample is covered by several windows
+	padded := make([]float64, l+2*frameSize)
+	for i := 0; i < l; i++ {
+		padded[frameSize+i] = float64(samples[i])
+	}
+
+	numFrames := (len(padded)-frameSize)/hopSize + 1
+	window := hannWindow(frameSize)
+
+	// processFrame windows the frame starting at the given frame index and stores the processed result
+	processed := make([][]float64, numFrames)
+	processFrame := func(index int) {
+		start := index * hopSize
+		frame := make([]float64, frameSize)
+		for i := 0; i < frameSize; i++ {
+			frame[i] = padded[start+i] * window[i]
+		}
+		processed[index] = process(frame)
+	}
+
+	if workers == 1 {
+		for i := 0; i < numFrames; i++ {
+			processFrame(i)
+		}
+	} else {
+		indices := make(chan int)
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for index := range indices {
+					processFrame(index)
+				}
+			}()
+		}
+		for i := 0; i < numFrames; i++ {
+			indices <- i
+		}
+		close(indices)
+		wg.Wait()
+	}
+
+	// Overlap-add the processed frames in order, applying the synthesis window
+	output := make([]float64, len(padded))
+	windowSum := make([]float64, len(padded))
+	for index, frame := range processed {
+		if len(frame) != frameSize {
+			return nil, errors.New("processed frame has the wrong length")
+		}
+		start := index * hopSize
+		for i := 0; i < frameSize; i++ {
+			output[start+i] += frame[i] * window[i]
+			windowSum[start+i] += window[i] * window[i]
+		}
+	}
+
+	result := make([]int16, l)
+	for i := 0; i < l; i++ {
+		j := frameSize + i
+		value := 0.0
+		if windowSum[j] > 1e-9 {
+			value = math.Round(output[j] / windowSum[j])
+		}
+		if value > math.MaxInt16 {
+			value = math.MaxInt16
+		} else if value < math.MinInt16 {
+			value = math.MinInt16
+		}
+		result[i] = int16(value)
+	}
+
+	return result, nil
+}
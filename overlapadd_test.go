@@ -0,0 +1,75 @@
+package mixorama
+
+import (
+	"math"
+	"testing"
+)
+
+// Helper function to create a sine wave for testing
+func createSineWave(frequency float64, amplitude float64, sampleRate, numSamples int) []int16 {
+	waveform := make([]int16, numSamples)
+	for i := 0; i < numSamples; i++ {
+		waveform[i] = int16(amplitude * math.Sin(2*math.Pi*frequency*float64(i)/float64(sampleRate)))
+	}
+	return waveform
+}
+
+func TestProcessOverlapAddIdentity(t *testing.T) {
+	samples := createSineWave(440, 10000, 44100, 5000)
+	identity := func(frame []float64) []float64 { return frame }
+
+	result, err := ProcessOverlapAdd(samples, 512, 128, 1, identity)
+	if err != nil {
+		t.Fatalf("Error in ProcessOverlapAdd: %v", err)
+	}
+	if len(result) != len(samples) {
+		t.Fatalf("Expected %d samples, got %d", len(samples), len(result))
+	}
+	for i, v := range result {
+		if math.Abs(float64(v)-float64(samples[i])) > 1 {
+			t.Fatalf("Identity processing changed sample %d: expected %d, got %d", i, samples[i], v)
+		}
+	}
+}
+
+func TestProcessOverlapAddParallel(t *testing.T) {
+	samples := createSineWave(440, 10000, 44100, 20000)
+	halve := func(frame []float64) []float64 {
+		out := make([]float64, len(frame))
+		for i, v := range frame {
+			out[i] = v * 0.5
+		}
+		return out
+	}
+
+	serial, err := ProcessOverlapAdd(samples, 1024, 256, 1, halve)
+	if err != nil {
+		t.Fatalf("Error in serial ProcessOverlapAdd: %v", err)
+	}
+	for _, workers := range []int{0, 2, 8} {
+		parallel, err := ProcessOverlapAdd(samples, 1024, 256, workers, halve)
+		if err != nil {
+			t.Fatalf("Error in ProcessOverlapAdd with %d workers: %v", workers, err)
+		}
+		for i := range serial {
+			if serial[i] != parallel[i] {
+				t.Fatalf("Output with %d workers differs from serial output at index %d", workers, i)
+			}
+		}
+	}
+}
+
+func TestProcessOverlapAddErrors(t *testing.T) {
+	samples := createTestWaveform(1000, 100)
+	identity := func(frame []float64) []float64 { return frame }
+
+	if _, err := ProcessOverlapAdd(samples, 0, 1, 1, identity); err == nil {
+		t.Error("Expected error for zero frame size")
+	}
+	if _, err := ProcessOverlapAdd(samples, 64, 48, 1, identity); err == nil {
+		t.Error("Expected error for a hop size larger than half the frame size")
+	}
+	if _, err := ProcessOverlapAdd(samples, 64, 16, -1, identity); err == nil {
+		t.Error("Expected error for a negative worker count")
+	}
+}
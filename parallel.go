@@ -0,0 +1,36 @@
+package mixorama
+
+import (
+	"runtime"
+	"sync"
+)
+
+// parallelThreshold is the number of input samples (the number of samples per track times the number of tracks)
+// above which inParallel splits the work across goroutines
+const parallelThreshold = 1 << 20
+
+// inParallel calls process for ranges that together cover 0 to numSamples. If the total number of input samples is
+// above parallelThreshold, the range is split into one part per GOMAXPROCS, which are processed concurrently.
+// Otherwise process is called once for the whole range.
+func inParallel(numSamples, numTracks int, process func(start, end int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if numSamples*numTracks <= parallelThreshold || workers < 2 || numSamples < workers {
+		process(0, numSamples)
+		return
+	}
+
+	chunkSize := (numSamples + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < numSamples; start += chunkSize {
+		end := start + chunkSize
+		if end > numSamples {
+			end = numSamples
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			process(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,89 @@
+package mixorama
+
+import (
+	"runtime"
+	"testing"
+)
+
+// createTracks returns numTracks sine waves with different frequencies, for testing large mixes
+func createTracks(numTracks, numSamples int) [][]int16 {
+	tracks := make([][]int16, numTracks)
+	for i := range tracks {
+		tracks[i] = createSineWave(float64(100+i*37), 4000, 44100, numSamples)
+	}
+	return tracks
+}
+
+func TestParallelMixingMatchesSerial(t *testing.T) {
+	// Make sure that the work is split, also on machines with a single CPU
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	// Large enough to be mixed in parallel
+	tracks := createTracks(16, parallelThreshold/8)
+
+	serialLinear := make([]int16, len(tracks[0]))
+	linearSummationRange(tracks, serialLinear, 0, len(serialLinear))
+	serialRMS := make([]int16, len(tracks[0]))
+	rmsMixingRange(tracks, serialRMS, 0, len(serialRMS), 1)
+
+	linear, err := LinearSummation(tracks...)
+	if err != nil {
+		t.Fatalf("Error in LinearSummation: %v", err)
+	}
+	rms, err := RMSMixing(tracks...)
+	if err != nil {
+		t.Fatalf("Error in RMSMixing: %v", err)
+	}
+
+	for i := range serialLinear {
+		if linear[i] != serialLinear[i] {
+			t.Fatalf("LinearSummation differs from the serial version at index %d: expected %d, got %d", i, serialLinear[i], linear[i])
+		}
+		if rms[i] != serialRMS[i] {
+			t.Fatalf("RMSMixing differs from the serial version at index %d: expected %d, got %d", i, serialRMS[i], rms[i])
+		}
+	}
+}
+
+func TestInParallelCoversRange(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	for _, numSamples := range []int{0, 1, 7, parallelThreshold + 3} {
+		covered := make([]int, numSamples)
+		inParallel(numSamples, 2, func(start, end int) {
+			for i := start; i < end; i++ {
+				covered[i]++
+			}
+		})
+		for i, count := range covered {
+			if count != 1 {
+				t.Fatalf("Expected index %d of %d to be processed once, got %d", i, numSamples, count)
+			}
+		}
+	}
+}
+
+func BenchmarkLinearSummation(b *testing.B) {
+	tracks := createTracks(32, 44100*10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		LinearSummation(tracks...)
+	}
+}
+
+func BenchmarkLinearSummationSerial(b *testing.B) {
+	tracks := createTracks(32, 44100*10)
+	combined := make([]int16, len(tracks[0]))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		linearSummationRange(tracks, combined, 0, len(combined))
+	}
+}
+
+func BenchmarkRMSMixing(b *testing.B) {
+	tracks := createTracks(32, 44100*10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		RMSMixing(tracks...)
+	}
+}
@@ -0,0 +1,143 @@
+package mixorama
+
+import "math"
+
+// ResampleQuality selects the interpolation that is used when resampling
+type ResampleQuality int
+
+const (
+	// ResampleLinear uses linear interpolation between neighbouring samples, which is fast but aliases
+	ResampleLinear ResampleQuality = iota
+	// ResampleCubic uses Catmull-Rom cubic interpolation through the four nearest samples
+	ResampleCubic
+	// ResampleSinc uses windowed-sinc interpolation, which is the slowest but most accurate method,
+	// and which also low-pass filters the samples when downsampling, to avoid aliasing
+	ResampleSinc
+)
+
+// sincHalfWidth is the number of input samples on each side of the output position that ResampleSinc uses
+const sincHalfWidth = 16
+
+// Resample converts mono samples from one sample rate to another, using linear interpolation.
+// The output has len(samples)*toRate/fromRate samples.
+func Resample(samples []int16, fromRate, toRate int) []int16 {
+	return ResampleChannels(samples, fromRate, toRate, 1)
+}
+
+// ResampleChannels converts interleaved samples with the given number of channels from one sample rate to another,
+// using linear interpolation. Each channel is resampled separately, so the interleaved layout is preserved.
+// If any of the rates or the number of channels is not positive, nil is returned.
+func ResampleChannels(samples []int16, fromRate, toRate, numChannels int) []int16 {
+	return ResampleWithQuality(samples, fromRate, toRate, numChannels, ResampleLinear)
+}
+
+// ResampleWithQuality works like ResampleChannels, but uses the interpolation that is selected by quality.
+// If any of the rates or the number of channels is not positive, nil is returned.
+func ResampleWithQuality(samples []int16, fromRate, toRate, numChannels int, quality ResampleQuality) []int16 {
+	if fromRate <= 0 || toRate <= 0 || numChannels <= 0 {
+		return nil
+	}
+
+	numFrames := len(samples) / numChannels
+	if fromRate == toRate {
+		resampled := make([]int16, numFrames*numChannels)
+		copy(resampled, samples)
+		return resampled
+	}
+
+	outFrames := int(int64(numFrames) * int64(toRate) / int64(fromRate))
+	return interpolateWith(samples, float64(fromRate)/float64(toRate), outFrames, numChannels, quality)
+}
+
+// ChangeSpeed changes the playback speed of mono samples by resampling, which also changes the pitch.
+// A factor of 2.0 plays twice as fast, with half as many samples. If factor is not positive, nil is returned.
+func ChangeSpeed(samples []int16, factor float64) []int16 {
+	return ChangeSpeedChannels(samples, factor, 1)
+}
+
+// ChangeSpeedChannels changes the playback speed of interleaved samples with the given number of channels,
+// like ChangeSpeed. If factor or the number of channels is not positive, nil is returned.
+func ChangeSpeedChannels(samples []int16, factor float64, numChannels int) []int16 {
+	if factor <= 0 || numChannels <= 0 {
+		return nil
+	}
+	numFrames := len(samples) / numChannels
+	return interpolate(samples, factor, int(float64(numFrames)/factor), numChannels)
+}
+
+// interpolate produces outFrames frames by stepping through the interleaved samples ratio input frames
+// at a time, using linear interpolation between neighbouring frames
+func interpolate(samples []int16, ratio float64, outFrames, numChannels int) []int16 {
+	return interpolateWith(samples, ratio, outFrames, numChannels, ResampleLinear)
+}
+
+// interpolateWith produces outFrames frames by stepping through the interleaved samples ratio input frames
+// at a time, using the interpolation that is selected by quality. Frames before the start and after the end
+// are treated as copies of the first and the last frame.
+func interpolateWith(samples []int16, ratio float64, outFrames, numChannels int, quality ResampleQuality) []int16 {
+	numFrames := len(samples) / numChannels
+	resampled := make([]int16, outFrames*numChannels)
+	if numFrames == 0 {
+		return resampled
+	}
+
+	// at returns the sample of channel c in frame i, clamped to the available frames
+	at := func(i, c int) float64 {
+		i = max(0, min(i, numFrames-1))
+		return float64(samples[i*numChannels+c])
+	}
+
+	// When downsampling, the sinc kernel is stretched so that it also filters out frequencies above the new Nyquist
+	cutoff := math.Min(1, 1/ratio)
+	halfWidth := int(math.Ceil(sincHalfWidth / cutoff))
+
+	for j := 0; j < outFrames; j++ {
+		position := float64(j) * ratio
+		i0 := int(math.Floor(position))
+		if i0 > numFrames-1 {
+			i0 = numFrames - 1
+		}
+		frac := position - float64(i0)
+
+		for c := 0; c < numChannels; c++ {
+			var value float64
+			switch quality {
+			case ResampleCubic:
+				p0, p1, p2, p3 := at(i0-1, c), at(i0, c), at(i0+1, c), at(i0+2, c)
+				value = p1 + 0.5*frac*(p2-p0+frac*(2*p0-5*p1+4*p2-p3+frac*(3*(p1-p2)+p3-p0)))
+			case ResampleSinc:
+				sum, weights := 0.0, 0.0
+				for k := i0 - halfWidth + 1; k <= i0+halfWidth; k++ {
+					x := position - float64(k)
+					weight := cutoff * sinc(cutoff*x) * blackmanWindow(x/float64(halfWidth))
+					sum += weight * at(k, c)
+					weights += weight
+				}
+				// Normalize, so that a constant signal stays constant
+				value = sum / weights
+			default:
+				s0, s1 := at(i0, c), at(i0+1, c)
+				value = s0 + frac*(s1-s0)
+			}
+			resampled[j*numChannels+c] = clampToInt16(value)
+		}
+	}
+
+	return resampled
+}
+
+// sinc returns the normalized sinc function, sin(pi*x)/(pi*x)
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// blackmanWindow returns the Blackman window at x, which is 1 at x=0 and falls to 0 at x=-1 and x=1
+func blackmanWindow(x float64) float64 {
+	if x <= -1 || x >= 1 {
+		return 0
+	}
+	return 0.42 + 0.5*math.Cos(math.Pi*x) + 0.08*math.Cos(2*math.Pi*x)
+}
@@ -0,0 +1,131 @@
+package mixorama
+
+import (
+	"math"
+	"testing"
+)
+
+func TestResampleLength(t *testing.T) {
+	samples := createTestWaveform(1000, 44100)
+
+	upsampled := Resample(samples, 44100, 48000)
+	if len(upsampled) != 48000 {
+		t.Errorf("Expected 48000 samples after upsampling, got %d", len(upsampled))
+	}
+
+	downsampled := Resample(samples, 44100, 22050)
+	if len(downsampled) != 22050 {
+		t.Errorf("Expected 22050 samples after downsampling, got %d", len(downsampled))
+	}
+}
+
+func TestResampleDC(t *testing.T) {
+	samples := createTestWaveform(1234, 1000)
+	for _, toRate := range []int{22050, 48000, 96000} {
+		for i, v := range Resample(samples, 44100, toRate) {
+			if v != 1234 {
+				t.Fatalf("Expected a DC signal to stay constant when resampling to %d Hz, got %d at index %d", toRate, v, i)
+			}
+		}
+	}
+}
+
+func TestResampleChannels(t *testing.T) {
+	// Left is constant 1000 and right is constant -1000
+	samples := make([]int16, 2000)
+	for i := 0; i < len(samples); i += 2 {
+		samples[i] = 1000
+		samples[i+1] = -1000
+	}
+
+	resampled := ResampleChannels(samples, 44100, 48000, 2)
+	if len(resampled) != 2*(1000*48000/44100) {
+		t.Fatalf("Expected %d samples, got %d", 2*(1000*48000/44100), len(resampled))
+	}
+	for i := 0; i < len(resampled); i += 2 {
+		if resampled[i] != 1000 || resampled[i+1] != -1000 {
+			t.Fatalf("Expected the channels to stay separate, got %d and %d at frame %d", resampled[i], resampled[i+1], i/2)
+		}
+	}
+}
+
+func TestChangeSpeed(t *testing.T) {
+	samples := createTestWaveform(3000, 1000)
+
+	slower := ChangeSpeed(samples, 0.5)
+	if len(slower) != 2000 {
+		t.Fatalf("Expected 2000 samples at half speed, got %d", len(slower))
+	}
+	for i, v := range slower {
+		if v != 3000 {
+			t.Fatalf("Expected DC to be preserved, got %d at index %d", v, i)
+		}
+	}
+
+	if faster := ChangeSpeed(samples, 2); len(faster) != 500 {
+		t.Errorf("Expected 500 samples at double speed, got %d", len(faster))
+	}
+	if ChangeSpeed(samples, 0) != nil {
+		t.Error("Expected nil for a speed factor of 0")
+	}
+}
+
+func TestResampleWithQuality(t *testing.T) {
+	fromRate, toRate := 44100, 48000
+	samples := createSineWave(3000, 10000, fromRate, fromRate/10)
+
+	// maxError returns the largest difference from the ideal sine wave at the new sample rate, skipping the edges
+	maxError := func(quality ResampleQuality) float64 {
+		resampled := ResampleWithQuality(samples, fromRate, toRate, 1, quality)
+		if len(resampled) != len(samples)*toRate/fromRate {
+			t.Fatalf("Expected %d samples, got %d", len(samples)*toRate/fromRate, len(resampled))
+		}
+		largest := 0.0
+		for i := 100; i < len(resampled)-100; i++ {
+			expected := 10000 * math.Sin(2*math.Pi*3000*float64(i)/float64(toRate))
+			largest = math.Max(largest, math.Abs(float64(resampled[i])-expected))
+		}
+		return largest
+	}
+
+	linear := maxError(ResampleLinear)
+	cubic := maxError(ResampleCubic)
+	sinc := maxError(ResampleSinc)
+	if !(sinc < cubic && cubic < linear) {
+		t.Errorf("Expected sinc to have the lowest error and linear the highest, got linear %.1f, cubic %.1f and sinc %.1f", linear, cubic, sinc)
+	}
+	if sinc > 10 {
+		t.Errorf("Expected windowed-sinc interpolation to be accurate, got a max error of %.1f", sinc)
+	}
+
+	// ResampleChannels uses linear interpolation
+	channels := ResampleChannels(samples, fromRate, toRate, 1)
+	for i, v := range ResampleWithQuality(samples, fromRate, toRate, 1, ResampleLinear) {
+		if channels[i] != v {
+			t.Fatalf("Expected ResampleChannels to match ResampleLinear, got %d and %d at index %d", channels[i], v, i)
+		}
+	}
+}
+
+func TestResampleSincAntiAliasing(t *testing.T) {
+	// A 15 kHz tone is above the Nyquist frequency of 22050 Hz, so it should be removed, not folded down to 7050 Hz
+	samples := createSineWave(15000, 10000, 44100, 44100/10)
+	linear := ResampleWithQuality(samples, 44100, 22050, 1, ResampleLinear)
+	sinc := ResampleWithQuality(samples, 44100, 22050, 1, ResampleSinc)
+
+	linearAlias := DetectFrequencyMagnitude(linear[100:len(linear)-100], 22050, 7050)
+	sincAlias := DetectFrequencyMagnitude(sinc[100:len(sinc)-100], 22050, 7050)
+	if sincAlias > 100 || sincAlias*10 > linearAlias {
+		t.Errorf("Expected sinc resampling to suppress the alias at 7050 Hz, got magnitude %.1f (linear: %.1f)", sincAlias, linearAlias)
+	}
+
+	// DC is preserved by every method
+	dc := createTestWaveform(1234, 1000)
+	for _, quality := range []ResampleQuality{ResampleCubic, ResampleSinc} {
+		for i, v := range ResampleWithQuality(dc, 44100, 22050, 1, quality) {
+			if v != 1234 {
+				t.Fatalf("Expected a DC signal to stay constant with quality %d, got %d at index %d", quality, v, i)
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package mixorama
+
+import "sync"
+
+// RingBuffer is a fixed-size FIFO buffer of samples that can be written to by one goroutine, for instance
+// from a capture callback, and read from by another, for instance from a playback callback.
+// The buffer never blocks: when it is full, the oldest samples are dropped, and when it runs dry, silence is read.
+type RingBuffer struct {
+	mutex sync.Mutex
+	data  []int16
+	start int // index of the oldest sample
+	count int // number of buffered samples
+}
+
+// NewRingBuffer creates a RingBuffer that can hold up to capacity samples.
+// A capacity below 1 is treated as 1.
+func NewRingBuffer(capacity int) *RingBuffer {
+	return &RingBuffer{data: make([]int16, max(1, capacity))}
+}
+
+// Write appends the samples to the buffer. If there is not enough room, the oldest samples are overwritten,
+// so that the buffer always holds the newest samples. The number of samples that were dropped is returned.
+func (b *RingBuffer) Write(samples []int16) int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	size := len(b.data)
+	dropped := 0
+	if len(samples) > size {
+		// Only the last size samples can fit
+		dropped += len(samples) - size
+		samples = samples[len(samples)-size:]
+	}
+	if overflow := b.count + len(samples) - size; overflow > 0 {
+		dropped += overflow
+		b.start = (b.start + overflow) % size
+		b.count -= overflow
+	}
+
+	end := (b.start + b.count) % size
+	n := copy(b.data[end:], samples)
+	copy(b.data, samples[n:])
+	b.count += len(samples)
+
+	return dropped
+}
+
+// Read removes n samples from the buffer and returns them. If fewer than n samples are buffered,
+// the rest of the returned samples are zero (silence), so that playback can continue after an underrun.
+func (b *RingBuffer) Read(n int) []int16 {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	samples := make([]int16, max(0, n))
+	available := min(len(samples), b.count)
+	copied := copy(samples[:available], b.data[b.start:])
+	copy(samples[copied:available], b.data)
+
+	b.start = (b.start + available) % len(b.data)
+	b.count -= available
+
+	return samples
+}
+
+// Len returns the number of samples that are currently buffered
+func (b *RingBuffer) Len() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.count
+}
+
+// Cap returns the number of samples that the buffer can hold
+func (b *RingBuffer) Cap() int {
+	return len(b.data)
+}
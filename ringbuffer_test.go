@@ -0,0 +1,99 @@
+package mixorama
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestRingBufferWraparound(t *testing.T) {
+	buffer := NewRingBuffer(5)
+
+	if dropped := buffer.Write([]int16{1, 2, 3, 4}); dropped != 0 {
+		t.Errorf("Expected no samples to be dropped, got %d", dropped)
+	}
+	if samples := buffer.Read(3); !slices.Equal(samples, []int16{1, 2, 3}) {
+		t.Errorf("Expected [1 2 3], got %v", samples)
+	}
+
+	// This write wraps around the end of the internal storage
+	if dropped := buffer.Write([]int16{5, 6, 7, 8}); dropped != 0 {
+		t.Errorf("Expected no samples to be dropped, got %d", dropped)
+	}
+	if buffer.Len() != 5 {
+		t.Errorf("Expected 5 buffered samples, got %d", buffer.Len())
+	}
+	if samples := buffer.Read(5); !slices.Equal(samples, []int16{4, 5, 6, 7, 8}) {
+		t.Errorf("Expected [4 5 6 7 8], got %v", samples)
+	}
+	if buffer.Len() != 0 {
+		t.Errorf("Expected an empty buffer, got %d samples", buffer.Len())
+	}
+}
+
+func TestRingBufferOverflow(t *testing.T) {
+	buffer := NewRingBuffer(4)
+	buffer.Write([]int16{1, 2, 3})
+
+	// The oldest samples are dropped to make room for the new ones
+	if dropped := buffer.Write([]int16{4, 5, 6}); dropped != 2 {
+		t.Errorf("Expected 2 samples to be dropped, got %d", dropped)
+	}
+	if samples := buffer.Read(4); !slices.Equal(samples, []int16{3, 4, 5, 6}) {
+		t.Errorf("Expected [3 4 5 6], got %v", samples)
+	}
+
+	// A write that is larger than the buffer keeps only the newest samples
+	if dropped := buffer.Write([]int16{1, 2, 3, 4, 5, 6, 7}); dropped != 3 {
+		t.Errorf("Expected 3 samples to be dropped, got %d", dropped)
+	}
+	if samples := buffer.Read(4); !slices.Equal(samples, []int16{4, 5, 6, 7}) {
+		t.Errorf("Expected [4 5 6 7], got %v", samples)
+	}
+}
+
+func TestRingBufferUnderrun(t *testing.T) {
+	buffer := NewRingBuffer(8)
+	buffer.Write([]int16{1, 2})
+
+	// Missing samples are read as silence
+	if samples := buffer.Read(5); !slices.Equal(samples, []int16{1, 2, 0, 0, 0}) {
+		t.Errorf("Expected [1 2 0 0 0], got %v", samples)
+	}
+	if samples := buffer.Read(3); !slices.Equal(samples, []int16{0, 0, 0}) {
+		t.Errorf("Expected silence from an empty buffer, got %v", samples)
+	}
+	if samples := buffer.Read(-1); len(samples) != 0 {
+		t.Errorf("Expected no samples for a negative count, got %v", samples)
+	}
+}
+
+func TestRingBufferConcurrent(t *testing.T) {
+	buffer := NewRingBuffer(1024)
+	const total = 100000
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		chunk := createTestWaveform(1, 100)
+		for i := 0; i < total/len(chunk); i++ {
+			buffer.Write(chunk)
+		}
+	}()
+
+	read := 0
+	for i := 0; i < total/64; i++ {
+		for _, v := range buffer.Read(64) {
+			if v != 0 && v != 1 {
+				t.Fatalf("Expected only written samples or silence, got %d", v)
+			}
+			read += int(v)
+		}
+	}
+	wg.Wait()
+
+	if read+buffer.Len() > total {
+		t.Errorf("Expected at most %d samples to be read, got %d", total, read+buffer.Len())
+	}
+}
@@ -1,8 +1,14 @@
 package mixorama
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"math"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/go-audio/audio"
 	"github.com/go-audio/wav"
@@ -17,60 +23,178 @@ func LoadWav(filename string) ([]int16, int, error) {
 	}
 	defer f.Close()
 
-	decoder := wav.NewDecoder(f)
-	buffer, err := decoder.FullPCMBuffer()
+	return DecodeWav(f)
+}
+
+// LoadWavMulti loads a .wav file with any number of channels and returns its interleaved samples as []int16,
+// along with the sample rate and the number of channels. Unlike LoadWav, mono files are not converted to stereo.
+func LoadWavMulti(filename string) ([]int16, int, int, error) {
+	f, err := os.Open(filename)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, 0, err
 	}
+	defer f.Close()
 
-	intBuffer := buffer
-	numChannels := intBuffer.Format.NumChannels
+	return decodeWavMulti(f)
+}
+
+// DecodeWav decodes .wav data from a reader and returns its samples as []int16 (stereo) along with the sample rate.
+// If the data is mono, it converts it to stereo by duplicating the mono channel to both the left and right channels.
+// Readers that can not seek are read into memory first.
+func DecodeWav(r io.Reader) ([]int16, int, error) {
+	samples, sampleRate, numChannels, err := decodeWavMulti(r)
+	if err != nil {
+		return nil, 0, err
+	}
 
 	if numChannels == 1 {
 		// Convert mono to stereo by duplicating the mono channel
-		l := len(intBuffer.Data)
+		l := len(samples)
 		stereoSamples := make([]int16, l*2)
 		for i := 0; i < l; i++ {
-			monoSample := int16(intBuffer.Data[i])
+			monoSample := samples[i]
 			// Copy the mono sample to both left and right channels
 			stereoSamples[2*i] = monoSample   // Left channel
 			stereoSamples[2*i+1] = monoSample // Right channel
 		}
-		return stereoSamples, intBuffer.Format.SampleRate, nil
+		return stereoSamples, sampleRate, nil
 	}
 
-	// If stereo, just convert to []int16 directly
+	return samples, sampleRate, nil
+}
+
+// decodeWavMulti decodes .wav data from a reader and returns the interleaved samples as []int16,
+// along with the sample rate and the number of channels
+func decodeWavMulti(r io.Reader) ([]int16, int, int, error) {
+	rs, ok := r.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		rs = bytes.NewReader(data)
+	}
+
+	decoder := wav.NewDecoder(rs)
+	intBuffer, err := decoder.FullPCMBuffer()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	bitDepth := int(decoder.BitDepth)
 	l := len(intBuffer.Data)
-	stereoSamples := make([]int16, l)
+	samples := make([]int16, l)
 	for i := 0; i < l; i++ {
-		stereoSamples[i] = int16(intBuffer.Data[i])
+		samples[i] = wavSampleToInt16(intBuffer.Data[i], bitDepth)
 	}
 
-	return stereoSamples, intBuffer.Format.SampleRate, nil
+	return samples, intBuffer.Format.SampleRate, intBuffer.Format.NumChannels, nil
+}
+
+// wavSampleToInt16 scales a decoded .wav sample from the bit depth of the file to 16 bits.
+// 8-bit .wav samples are unsigned, so their offset of 128 is removed first.
+func wavSampleToInt16(sample, bitDepth int) int16 {
+	switch {
+	case bitDepth == 8:
+		return int16((sample - 128) << 8)
+	case bitDepth > 16:
+		return int16(sample >> (bitDepth - 16))
+	case bitDepth < 16:
+		return int16(sample << (16 - bitDepth))
+	default:
+		return int16(sample)
+	}
 }
 
 // SaveWav saves a slice of int16 samples as a .wav file
 func SaveWav(filename string, samples []int16, sampleRate int) error {
+	return saveWav(filename, samples, sampleRate, 1)
+}
+
+// saveWav saves a slice of interleaved int16 samples with the given number of channels as a .wav file
+func saveWav(filename string, samples []int16, sampleRate, numChannels int) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return EncodeWav(f, samples, sampleRate, numChannels)
+}
+
+// SaveWavChecked saves a slice of interleaved int16 samples with the given number of channels as a 16-bit .wav file,
+// like SaveWav, and returns how many of the samples are at full scale, which usually means that they were clipped
+func SaveWavChecked(filename string, samples []int16, sampleRate, numChannels int) (clippedCount int, err error) {
+	if err := saveWav(filename, samples, sampleRate, numChannels); err != nil {
+		return 0, err
+	}
+	return CountClippedSamples(samples), nil
+}
+
+// SaveWavBitDepth saves a slice of interleaved int16 samples with the given number of channels as a .wav file
+// with a bit depth of 8, 16, 24 or 32. The samples are scaled to the full range of the bit depth,
+// and 8-bit samples are stored as unsigned values, as the .wav format requires.
+func SaveWavBitDepth(filename string, samples []int16, sampleRate, numChannels, bitDepth int) error {
+	if bitDepth != 8 && bitDepth != 16 && bitDepth != 24 && bitDepth != 32 {
+		return fmt.Errorf("unsupported bit depth: %d", bitDepth)
+	}
+
 	f, err := os.Create(filename)
 	if err != nil {
 		return err
 	}
 	defer f.Close()
 
-	encoder := wav.NewEncoder(f, sampleRate, 16, 1, 1)
+	return encodeWav(f, samples, sampleRate, numChannels, bitDepth)
+}
+
+// EncodeWav writes a slice of interleaved int16 samples with the given number of channels as 16-bit .wav data.
+// Writers that can not seek are written to after the data has been encoded in memory.
+func EncodeWav(w io.Writer, samples []int16, sampleRate, numChannels int) error {
+	return encodeWav(w, samples, sampleRate, numChannels, 16)
+}
+
+// encodeWav writes a slice of interleaved int16 samples as .wav data with the given bit depth
+func encodeWav(w io.Writer, samples []int16, sampleRate, numChannels, bitDepth int) error {
+	ws, ok := w.(io.WriteSeeker)
+	var buffer *writeSeekBuffer
+	if !ok {
+		buffer = &writeSeekBuffer{}
+		ws = buffer
+	}
+
+	encoder := wav.NewEncoder(ws, sampleRate, bitDepth, numChannels, 1)
 	intBuffer := &audio.IntBuffer{
 		Data:           make([]int, len(samples)),
-		Format:         &audio.Format{SampleRate: sampleRate, NumChannels: 1},
-		SourceBitDepth: 16,
+		Format:         &audio.Format{SampleRate: sampleRate, NumChannels: numChannels},
+		SourceBitDepth: bitDepth,
 	}
 	for i, sample := range samples {
-		intBuffer.Data[i] = int(sample)
+		switch bitDepth {
+		case 8:
+			// 8-bit .wav samples are unsigned, with silence at 128
+			intBuffer.Data[i] = int(sample>>8) + 128
+		case 24:
+			intBuffer.Data[i] = int(sample) << 8
+		case 32:
+			intBuffer.Data[i] = int(sample) << 16
+		default:
+			intBuffer.Data[i] = int(sample)
+		}
 	}
 
 	if err := encoder.Write(intBuffer); err != nil {
 		return err
 	}
-	return encoder.Close()
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	if buffer != nil {
+		_, err := w.Write(buffer.data)
+		return err
+	}
+	return nil
 }
 
 // PadSamples pads the shorter sample with zeros (silence) so that both samples have the same length.
@@ -95,22 +219,180 @@ func PadSamples(wave1, wave2 []int16) ([]int16, []int16) {
 	return wave1, paddedWave2
 }
 
-// LowPassFilter is a simple low-pass filter that can remove high frequencies
+// PadSamplesInfo works like PadSamples, but also returns how many zero samples were added to the end of each sample,
+// so that callers can keep track of the alignment. At most one of the pad counts is larger than zero.
+func PadSamplesInfo(wave1, wave2 []int16) (p1, p2 []int16, padded1, padded2 int) {
+	p1, p2 = PadSamples(wave1, wave2)
+	return p1, p2, len(p1) - len(wave1), len(p2) - len(wave2)
+}
+
+// PadTo returns a copy of the samples that is exactly targetLength samples long,
+// either padded with zeros (silence) at the end or truncated.
+func PadTo(samples []int16, targetLength int) []int16 {
+	if targetLength < 0 {
+		targetLength = 0
+	}
+	paddedSamples := make([]int16, targetLength)
+	copy(paddedSamples, samples)
+	return paddedSamples
+}
+
+// PadToDuration returns a copy of the interleaved samples that is padded with zeros or truncated so that
+// it plays for exactly the given duration, rounded down to a whole frame.
+// If the sample rate or the number of channels is not positive, nil is returned.
+func PadToDuration(samples []int16, sampleRate, numChannels int, d time.Duration) []int16 {
+	if sampleRate <= 0 || numChannels <= 0 {
+		return nil
+	}
+	numFrames := int64(d) * int64(sampleRate) / int64(time.Second)
+	return PadTo(samples, int(numFrames)*numChannels)
+}
+
+// Duration returns the playback duration of interleaved samples with the given sample rate and number of channels.
+// If the sample rate or the number of channels is not positive, 0 is returned.
+func Duration(samples []int16, sampleRate, numChannels int) time.Duration {
+	if sampleRate <= 0 || numChannels <= 0 {
+		return 0
+	}
+	numFrames := int64(len(samples) / numChannels)
+	return time.Duration(numFrames * int64(time.Second) / int64(sampleRate))
+}
+
+// Concat appends the given sample slices back to back, in order
+func Concat(waves ...[]int16) []int16 {
+	total := 0
+	for _, wave := range waves {
+		total += len(wave)
+	}
+	result := make([]int16, 0, total)
+	for _, wave := range waves {
+		result = append(result, wave...)
+	}
+	return result
+}
+
+// ConcatChannels appends the given interleaved sample slices back to back, in order.
+// It returns an error if the length of any of the slices is not a multiple of numChannels,
+// since that would shift the channels of the slices that follow.
+func ConcatChannels(numChannels int, waves ...[]int16) ([]int16, error) {
+	if numChannels <= 0 {
+		return nil, errors.New("number of channels must be positive")
+	}
+	for _, wave := range waves {
+		if len(wave)%numChannels != 0 {
+			return nil, errors.New("sample length must be a multiple of the number of channels")
+		}
+	}
+	return Concat(waves...), nil
+}
+
+// Chunk splits the samples into chunks of chunkSize samples. The last chunk may be shorter.
+// If chunkSize is not positive, nil is returned.
+func Chunk(samples []int16, chunkSize int) [][]int16 {
+	if chunkSize <= 0 {
+		return nil
+	}
+	l := len(samples)
+	chunks := make([][]int16, 0, (l+chunkSize-1)/chunkSize)
+	for start := 0; start < l; start += chunkSize {
+		end := start + chunkSize
+		if end > l {
+			end = l
+		}
+		chunk := make([]int16, end-start)
+		copy(chunk, samples[start:end])
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// ChunkPadded splits the samples into chunks of chunkSize samples, like Chunk,
+// but pads the last chunk with zeros (silence) so that all chunks have the same size
+func ChunkPadded(samples []int16, chunkSize int) [][]int16 {
+	chunks := Chunk(samples, chunkSize)
+	if last := len(chunks) - 1; last >= 0 && len(chunks[last]) < chunkSize {
+		padded := make([]int16, chunkSize)
+		copy(padded, chunks[last])
+		chunks[last] = padded
+	}
+	return chunks
+}
+
+// LowPassFilter is a simple low-pass filter that can remove high frequencies.
+// The samples are filtered as a single channel. Use LowPassFilterChannels for interleaved samples.
 func LowPassFilter(samples []int16, sampleRate int, cutoffFrequency float64) []int16 {
+	return LowPassFilterChannels(samples, sampleRate, cutoffFrequency, 1)
+}
+
+// LowPassFilterChannels works like LowPassFilter, but for interleaved samples with the given number of channels.
+// Each channel is filtered separately, so that a sample is never smoothed towards a sample from another channel.
+// If the number of channels is not positive, nil is returned.
+func LowPassFilterChannels(samples []int16, sampleRate int, cutoffFrequency float64, numChannels int) []int16 {
+	if numChannels <= 0 {
+		return nil
+	}
+
 	rc := 1.0 / (2.0 * math.Pi * cutoffFrequency)
 	dt := 1.0 / float64(sampleRate)
 	alpha := dt / (rc + dt)
 
 	filteredSamples := make([]int16, len(samples))
+	copy(filteredSamples, samples[:min(numChannels, len(samples))])
+
+	for i := numChannels; i < len(samples); i++ {
+		previous := float64(filteredSamples[i-numChannels])
+		filteredSamples[i] = int16(previous + alpha*(float64(samples[i])-previous))
+	}
+
+	return filteredSamples
+}
+
+// HighPassFilter is a simple high-pass filter that can remove low frequencies, such as DC offset and rumble
+func HighPassFilter(samples []int16, sampleRate int, cutoffFrequency float64) []int16 {
+	rc := 1.0 / (2.0 * math.Pi * cutoffFrequency)
+	dt := 1.0 / float64(sampleRate)
+	alpha := rc / (rc + dt)
+
+	filteredSamples := make([]int16, len(samples))
+	if len(samples) == 0 {
+		return filteredSamples
+	}
 	filteredSamples[0] = samples[0]
 
+	// Keep the previous output as a float64 so that rounding errors do not accumulate
+	previous := float64(samples[0])
 	for i := 1; i < len(samples); i++ {
-		filteredSamples[i] = int16(float64(filteredSamples[i-1]) + alpha*(float64(samples[i])-float64(filteredSamples[i-1])))
+		filtered := alpha * (previous + float64(samples[i]) - float64(samples[i-1]))
+		previous = filtered
+		if filtered > math.MaxInt16 {
+			filtered = math.MaxInt16
+		} else if filtered < math.MinInt16 {
+			filtered = math.MinInt16
+		}
+		filteredSamples[i] = int16(filtered)
 	}
 
 	return filteredSamples
 }
 
+// RemoveDCOffset subtracts the mean of the samples from each sample, clamping the result to the int16 range
+func RemoveDCOffset(samples []int16) []int16 {
+	l := len(samples)
+	if l == 0 {
+		return []int16{}
+	}
+	sum := 0.0
+	for _, sample := range samples {
+		sum += float64(sample)
+	}
+	mean := sum / float64(l)
+	result := make([]int16, l)
+	for i := 0; i < l; i++ {
+		result[i] = clampToInt16(float64(samples[i]) - mean)
+	}
+	return result
+}
+
 // NormalizeSamples scales the samples so the peak amplitude matches the given max amplitude
 func NormalizeSamples(samples []int16, targetPeak int16) []int16 {
 	// Find the current peak amplitude
@@ -141,31 +423,164 @@ func NormalizeSamples(samples []int16, targetPeak int16) []int16 {
 	return normalizedSamples
 }
 
-// FindPeakAmplitude returns the maximum absolute amplitude in the sample set
+// ErrSilentInput is returned when samples that are all zero can not be processed, for instance when normalizing
+var ErrSilentInput = errors.New("input is silent")
+
+// NormalizeSamplesErr scales the samples so the peak amplitude matches the given max amplitude, like NormalizeSamples,
+// but returns ErrSilentInput instead of the unchanged samples if the peak amplitude is zero
+func NormalizeSamplesErr(samples []int16, targetPeak int16) ([]int16, error) {
+	if FindPeakAmplitude(samples) == 0 {
+		return nil, ErrSilentInput
+	}
+	return NormalizeSamples(samples, targetPeak), nil
+}
+
+// batchOutputFiles returns the path in outputDir for each of the files, with the same name as the file.
+// An error is returned if two files have the same name, or if an output path is the same as one of the files.
+func batchOutputFiles(files []string, outputDir string) ([]string, error) {
+	inputs := make(map[string]string, len(files))
+	for _, file := range files {
+		absFile, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
+		}
+		inputs[absFile] = file
+	}
+
+	var errs []error
+	outputFiles := make([]string, len(files))
+	names := make(map[string]string, len(files))
+	for i, file := range files {
+		name := filepath.Base(file)
+		if other, ok := names[name]; ok {
+			errs = append(errs, fmt.Errorf("%s and %s would both be saved as %s", other, file, name))
+			continue
+		}
+		names[name] = file
+		outputFiles[i] = filepath.Join(outputDir, name)
+		absOutput, err := filepath.Abs(outputFiles[i])
+		if err != nil {
+			return nil, err
+		}
+		if input, ok := inputs[absOutput]; ok {
+			errs = append(errs, fmt.Errorf("saving %s would overwrite %s", outputFiles[i], input))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return outputFiles, nil
+}
+
+// NormalizeBatch loads each of the .wav files, normalizes it to the target peak and saves it with the same name
+// in outputDir, keeping the sample rate and the number of channels. If a file can not be loaded, is silent or
+// can not be saved, the remaining files are still processed, and all the errors are returned together.
+// If two files have the same name, or if an output file would overwrite one of the files, no files are processed.
+func NormalizeBatch(files []string, targetPeak int16, outputDir string) error {
+	outputFiles, err := batchOutputFiles(files, outputDir)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i, file := range files {
+		samples, sampleRate, numChannels, err := LoadWavMulti(file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to load %s: %w", file, err))
+			continue
+		}
+		normalized, err := NormalizeSamplesErr(samples, targetPeak)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+			continue
+		}
+		outputFile := outputFiles[i]
+		if err := saveWav(outputFile, normalized, sampleRate, numChannels); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save %s: %w", outputFile, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NormalizeSamplesHeadroom scales the samples so the peak amplitude matches the given level in dBFS,
+// for instance -1.0 to leave 1 dB of headroom for further processing. Levels above 0 dBFS are treated as 0 dBFS.
+func NormalizeSamplesHeadroom(samples []int16, targetDBFS float64) []int16 {
+	if targetDBFS > 0 {
+		targetDBFS = 0
+	}
+	targetPeak := int16(math.Round(math.MaxInt16 * math.Pow(10, targetDBFS/20)))
+	return NormalizeSamples(samples, targetPeak)
+}
+
+// ApplyGain multiplies the samples by the given factor, clamping the result to the int16 range
+func ApplyGain(samples []int16, factor float64) []int16 {
+	amplifiedSamples := make([]int16, len(samples))
+	for i, sample := range samples {
+		amplifiedSamples[i] = clampToInt16(float64(sample) * factor)
+	}
+	return amplifiedSamples
+}
+
+// ApplyGainDB amplifies or attenuates the samples by the given number of dB, clamping the result to the int16 range.
+// A gain of math.Inf(-1) silences the samples.
+func ApplyGainDB(samples []int16, gainDB float64) []int16 {
+	return ApplyGain(samples, math.Pow(10, gainDB/20))
+}
+
+// InvertPolarity negates each sample. Since the negation of math.MinInt16 does not fit in an int16,
+// it becomes math.MaxInt16.
+func InvertPolarity(samples []int16) []int16 {
+	invertedSamples := make([]int16, len(samples))
+	for i, sample := range samples {
+		if sample == math.MinInt16 {
+			invertedSamples[i] = math.MaxInt16
+		} else {
+			invertedSamples[i] = -sample
+		}
+	}
+	return invertedSamples
+}
+
+// FindPeakAmplitude returns the maximum absolute amplitude in the sample set.
+// Since the absolute value of math.MinInt16 does not fit in an int16, it is counted as math.MaxInt16.
 func FindPeakAmplitude(samples []int16) int16 {
 	maxAmplitude := int16(0)
 	for _, sample := range samples {
-		if abs := int16(math.Abs(float64(sample))); abs > maxAmplitude {
+		abs := sample
+		if sample == math.MinInt16 {
+			abs = math.MaxInt16
+		} else if sample < 0 {
+			abs = -sample
+		}
+		if abs > maxAmplitude {
 			maxAmplitude = abs
 		}
 	}
 	return maxAmplitude
 }
 
-// AnalyzeHighestFrequency estimates the highest frequency in the audio signal
-func AnalyzeHighestFrequency(samples []int16, sampleRate int) float64 {
-	// Simple estimation: check zero crossings
-	zeroCrossings := 0
-	l := len(samples)
-	for i := 1; i < l; i++ {
-		if (samples[i-1] > 0 && samples[i] < 0) || (samples[i-1] < 0 && samples[i] > 0) {
-			zeroCrossings++
-		}
+// Headroom returns how many dB the samples can be amplified before the peak reaches full scale (0 dBFS).
+// Silent input has unlimited headroom, so +Inf is returned.
+func Headroom(samples []int16) float64 {
+	peak := FindPeakAmplitude(samples)
+	if peak == 0 {
+		return math.Inf(1)
 	}
+	return 20 * math.Log10(float64(math.MaxInt16)/float64(peak))
+}
 
-	// Highest frequency estimation based on zero crossings
-	duration := float64(l) / float64(sampleRate)
-	frequency := float64(zeroCrossings) / (2 * duration)
+// AnalyzeHighestFrequency estimates the highest frequency in the audio signal.
+// It returns the highest frequency in the spectrum that is within DefaultFrequencyThresholdDB of the strongest frequency.
+func AnalyzeHighestFrequency(samples []int16, sampleRate int) float64 {
+	return AnalyzeHighestFrequencyThreshold(samples, sampleRate, DefaultFrequencyThresholdDB)
+}
 
-	return frequency
+// clampToInt16 rounds the value and clamps it to the int16 range
+func clampToInt16(value float64) int16 {
+	value = math.Round(value)
+	if value > math.MaxInt16 {
+		return math.MaxInt16
+	} else if value < math.MinInt16 {
+		return math.MinInt16
+	}
+	return int16(value)
 }
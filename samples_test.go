@@ -1,9 +1,17 @@
 package mixorama
 
 import (
+	"errors"
+	"fmt"
 	"math"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/go-audio/wav"
 )
 
 func TestLoadWav(t *testing.T) {
@@ -35,6 +43,239 @@ func TestSaveWav(t *testing.T) {
 	}
 }
 
+func TestNormalizeBatch(t *testing.T) {
+	inputDir := t.TempDir()
+	outputDir := t.TempDir()
+	quiet := filepath.Join(inputDir, "quiet.wav")
+	loud := filepath.Join(inputDir, "loud.wav")
+
+	if err := SaveWav(quiet, createSineWave(440, 1000, 44100, 1000), 44100); err != nil {
+		t.Fatalf("Failed to save WAV file: %v", err)
+	}
+	if err := saveWav(loud, createSineWave(440, 30000, 44100, 2000), 44100, 2); err != nil {
+		t.Fatalf("Failed to save WAV file: %v", err)
+	}
+
+	if err := NormalizeBatch([]string{quiet, loud}, 20000, outputDir); err != nil {
+		t.Fatalf("Error in NormalizeBatch: %v", err)
+	}
+
+	for _, name := range []string{"quiet.wav", "loud.wav"} {
+		samples, sampleRate, _, err := LoadWavMulti(filepath.Join(outputDir, name))
+		if err != nil {
+			t.Fatalf("Failed to load the normalized %s: %v", name, err)
+		}
+		if sampleRate != 44100 {
+			t.Errorf("Expected sample rate 44100 for %s, got %d", name, sampleRate)
+		}
+		if peak := FindPeakAmplitude(samples); peak != 20000 {
+			t.Errorf("Expected %s to have a peak of 20000, got %d", name, peak)
+		}
+	}
+}
+
+func TestNormalizeBatchContinuesOnError(t *testing.T) {
+	inputDir := t.TempDir()
+	outputDir := t.TempDir()
+	missing := filepath.Join(inputDir, "missing.wav")
+	silent := filepath.Join(inputDir, "silent.wav")
+	valid := filepath.Join(inputDir, "valid.wav")
+
+	if err := SaveWav(silent, make([]int16, 100), 44100); err != nil {
+		t.Fatalf("Failed to save WAV file: %v", err)
+	}
+	if err := SaveWav(valid, createTestWaveform(1000, 100), 44100); err != nil {
+		t.Fatalf("Failed to save WAV file: %v", err)
+	}
+
+	err := NormalizeBatch([]string{missing, silent, valid}, 20000, outputDir)
+	if err == nil {
+		t.Fatal("Expected an error for the missing and the silent file")
+	}
+	if !strings.Contains(err.Error(), missing) {
+		t.Errorf("Expected the error to name the missing file, got: %v", err)
+	}
+	if !errors.Is(err, ErrSilentInput) {
+		t.Errorf("Expected the error to include ErrSilentInput, got: %v", err)
+	}
+
+	// The valid file after the failing ones should still be processed
+	if _, err := os.Stat(filepath.Join(outputDir, "valid.wav")); err != nil {
+		t.Errorf("Expected the valid file to be normalized: %v", err)
+	}
+}
+
+func TestNormalizeBatchOutputConflicts(t *testing.T) {
+	inputDir := t.TempDir()
+	first := filepath.Join(inputDir, "a", "take.wav")
+	second := filepath.Join(inputDir, "b", "take.wav")
+	for _, file := range []string{first, second} {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := SaveWav(file, createTestWaveform(1000, 100), 44100); err != nil {
+			t.Fatalf("Failed to save WAV file: %v", err)
+		}
+	}
+
+	outputDir := t.TempDir()
+	err := NormalizeBatch([]string{first, second}, 20000, outputDir)
+	if err == nil {
+		t.Fatal("Expected an error for two files with the same name")
+	}
+	if !strings.Contains(err.Error(), first) || !strings.Contains(err.Error(), second) {
+		t.Errorf("Expected the error to name both files, got: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "take.wav")); err == nil {
+		t.Error("Expected no files to be saved when the output names conflict")
+	}
+
+	if err := NormalizeBatch([]string{first}, 20000, filepath.Dir(first)); err == nil {
+		t.Error("Expected an error when the output file would overwrite the input file")
+	}
+	samples, _, err := LoadWav(first)
+	if err != nil {
+		t.Fatalf("Failed to load the input file: %v", err)
+	}
+	if peak := FindPeakAmplitude(samples); peak != 1000 {
+		t.Errorf("Expected the input file to be left unchanged, got a peak of %d", peak)
+	}
+}
+
+func TestSaveWavChecked(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test_checked.wav")
+	samples := []int16{1000, math.MaxInt16, -1000, math.MinInt16, math.MaxInt16, 32766}
+
+	clipped, err := SaveWavChecked(filename, samples, 44100, 2)
+	if err != nil {
+		t.Fatalf("Error in SaveWavChecked: %v", err)
+	}
+	if clipped != 3 {
+		t.Errorf("Expected 3 clipped samples, got %d", clipped)
+	}
+
+	loaded, sampleRate, numChannels, err := LoadWavMulti(filename)
+	if err != nil {
+		t.Fatalf("Failed to load the saved file: %v", err)
+	}
+	if sampleRate != 44100 || numChannels != 2 || len(loaded) != len(samples) {
+		t.Errorf("Expected %d samples in 2 channels at 44100 Hz, got %d samples in %d channels at %d Hz", len(samples), len(loaded), numChannels, sampleRate)
+	}
+
+	if _, err := SaveWavChecked(filepath.Join(t.TempDir(), "missing", "test.wav"), samples, 44100, 2); err == nil {
+		t.Error("Expected error when the file can not be created")
+	}
+}
+
+func TestSaveWavBitDepth(t *testing.T) {
+	samples := []int16{1000, -1000, 2000, -2000}
+	filename := filepath.Join(t.TempDir(), "test_24bit.wav")
+
+	if err := SaveWavBitDepth(filename, samples, 44100, 2, 24); err != nil {
+		t.Fatalf("Failed to save 24-bit WAV file: %v", err)
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatalf("Failed to open WAV file: %v", err)
+	}
+	defer f.Close()
+	decoder := wav.NewDecoder(f)
+	intBuffer, err := decoder.FullPCMBuffer()
+	if err != nil {
+		t.Fatalf("Failed to decode WAV file: %v", err)
+	}
+	if decoder.BitDepth != 24 {
+		t.Errorf("Expected a bit depth of 24, got %d", decoder.BitDepth)
+	}
+	if decoder.NumChans != 2 || decoder.SampleRate != 44100 {
+		t.Errorf("Expected 2 channels at 44100 Hz, got %d channels at %d Hz", decoder.NumChans, decoder.SampleRate)
+	}
+	for i, sample := range samples {
+		if expected := int(sample) << 8; intBuffer.Data[i] != expected {
+			t.Errorf("Expected 24-bit sample %d at index %d, got %d", expected, i, intBuffer.Data[i])
+		}
+	}
+
+	if err := SaveWavBitDepth(filename, samples, 44100, 2, 12); err == nil {
+		t.Error("Expected error for an unsupported bit depth")
+	}
+}
+
+func TestSaveWavBitDepthRoundTrip(t *testing.T) {
+	samples := []int16{1000, -1000, 20000, -20000, math.MaxInt16, math.MinInt16}
+	for _, bitDepth := range []int{8, 24, 32} {
+		filename := filepath.Join(t.TempDir(), fmt.Sprintf("test_%dbit.wav", bitDepth))
+		if err := SaveWavBitDepth(filename, samples, 44100, 2, bitDepth); err != nil {
+			t.Fatalf("Failed to save %d-bit WAV file: %v", bitDepth, err)
+		}
+
+		expected := samples
+		if bitDepth == 8 {
+			// Only the upper 8 bits of each sample are stored
+			expected = make([]int16, len(samples))
+			for i, sample := range samples {
+				expected[i] = sample >> 8 << 8
+			}
+		}
+
+		loaded, _, err := LoadWav(filename)
+		if err != nil {
+			t.Fatalf("Failed to load %d-bit WAV file: %v", bitDepth, err)
+		}
+		if !slices.Equal(loaded, expected) {
+			t.Errorf("Expected %v from LoadWav of a %d-bit file, got %v", expected, bitDepth, loaded)
+		}
+
+		reader, err := NewWavReader(filename)
+		if err != nil {
+			t.Fatalf("Failed to open %d-bit WAV file: %v", bitDepth, err)
+		}
+		chunk, err := reader.ReadChunk(len(samples))
+		reader.Close()
+		if err != nil {
+			t.Fatalf("Failed to read %d-bit WAV file: %v", bitDepth, err)
+		}
+		if !slices.Equal(chunk, expected) {
+			t.Errorf("Expected %v from ReadChunk of a %d-bit file, got %v", expected, bitDepth, chunk)
+		}
+	}
+}
+
+func TestLoadWavMulti(t *testing.T) {
+	// Create a 4-channel fixture where each channel has its own constant value
+	const numChannels, numFrames = 4, 1000
+	samples := make([]int16, numChannels*numFrames)
+	for i := range samples {
+		samples[i] = int16((i%numChannels + 1) * 1000)
+	}
+	filename := "test_quad.wav"
+	defer os.Remove(filename) // Cleanup after test
+
+	if err := saveWav(filename, samples, 48000, numChannels); err != nil {
+		t.Fatalf("Failed to save 4-channel WAV file: %v", err)
+	}
+
+	loaded, sampleRate, channels, err := LoadWavMulti(filename)
+	if err != nil {
+		t.Fatalf("Failed to load 4-channel WAV file: %v", err)
+	}
+	if channels != numChannels {
+		t.Errorf("Expected %d channels, got %d", numChannels, channels)
+	}
+	if sampleRate != 48000 {
+		t.Errorf("Expected sample rate 48000, got %d", sampleRate)
+	}
+	if len(loaded) != numChannels*numFrames {
+		t.Fatalf("Expected %d samples, got %d", numChannels*numFrames, len(loaded))
+	}
+	for i, v := range loaded {
+		if v != samples[i] {
+			t.Fatalf("Expected sample %d to be %d, got %d", i, samples[i], v)
+		}
+	}
+}
+
 func TestPadSamples(t *testing.T) {
 	wave1 := []int16{100, 200, 300}
 	wave2 := []int16{400, 500}
@@ -50,6 +291,174 @@ func TestPadSamples(t *testing.T) {
 	}
 }
 
+func TestPadSamplesInfo(t *testing.T) {
+	wave1 := []int16{100, 200, 300, 400, 500}
+	wave2 := []int16{600, 700}
+
+	p1, p2, padded1, padded2 := PadSamplesInfo(wave1, wave2)
+	if len(p1) != 5 || len(p2) != 5 {
+		t.Fatalf("Expected both padded waves to have 5 samples, got %d and %d", len(p1), len(p2))
+	}
+	if padded1 != 0 || padded2 != 3 {
+		t.Errorf("Expected pad counts 0 and 3, got %d and %d", padded1, padded2)
+	}
+
+	// The other way around
+	_, _, padded1, padded2 = PadSamplesInfo(wave2, wave1)
+	if padded1 != len(wave1)-len(wave2) || padded2 != 0 {
+		t.Errorf("Expected pad counts %d and 0, got %d and %d", len(wave1)-len(wave2), padded1, padded2)
+	}
+
+	_, _, padded1, padded2 = PadSamplesInfo(wave1, wave1)
+	if padded1 != 0 || padded2 != 0 {
+		t.Errorf("Expected no padding for equal lengths, got %d and %d", padded1, padded2)
+	}
+}
+
+func TestPadTo(t *testing.T) {
+	samples := []int16{100, 200, 300}
+
+	padded := PadTo(samples, 5)
+	expected := []int16{100, 200, 300, 0, 0}
+	if len(padded) != len(expected) {
+		t.Fatalf("Expected %d samples, got %d", len(expected), len(padded))
+	}
+	for i, v := range expected {
+		if padded[i] != v {
+			t.Errorf("Expected %d at index %d, got %d", v, i, padded[i])
+		}
+	}
+
+	truncated := PadTo(samples, 2)
+	if len(truncated) != 2 || truncated[0] != 100 || truncated[1] != 200 {
+		t.Errorf("Expected [100 200], got %v", truncated)
+	}
+
+	// The input should not be modified
+	truncated[0] = 0
+	if samples[0] != 100 {
+		t.Error("Expected PadTo to return a copy")
+	}
+}
+
+func TestPadToDuration(t *testing.T) {
+	samples := createTestWaveform(1000, 100)
+
+	padded := PadToDuration(samples, 1000, 2, 100*time.Millisecond)
+	if len(padded) != 200 {
+		t.Fatalf("Expected 200 samples (100 stereo frames), got %d", len(padded))
+	}
+	if padded[99] != 1000 || padded[100] != 0 || padded[199] != 0 {
+		t.Errorf("Expected the original samples followed by silence, got %d, %d and %d", padded[99], padded[100], padded[199])
+	}
+	if d := Duration(padded, 1000, 2); d != 100*time.Millisecond {
+		t.Errorf("Expected a duration of 100ms, got %v", d)
+	}
+
+	truncated := PadToDuration(samples, 1000, 1, 25*time.Millisecond)
+	if len(truncated) != 25 {
+		t.Errorf("Expected 25 samples, got %d", len(truncated))
+	}
+
+	if PadToDuration(samples, 0, 1, time.Second) != nil {
+		t.Error("Expected nil for an invalid sample rate")
+	}
+}
+
+func TestDuration(t *testing.T) {
+	if d := Duration(make([]int16, 88200), 44100, 2); d != time.Second {
+		t.Errorf("Expected one second of stereo samples, got %v", d)
+	}
+	if d := Duration(make([]int16, 4800), 48000, 1); d != 100*time.Millisecond {
+		t.Errorf("Expected 100ms of mono samples, got %v", d)
+	}
+	if d := Duration(make([]int16, 100), 0, 1); d != 0 {
+		t.Errorf("Expected 0 for an invalid sample rate, got %v", d)
+	}
+}
+
+func TestConcat(t *testing.T) {
+	first := createTestWaveform(1, 3)
+	second := createTestWaveform(2, 5)
+	third := createTestWaveform(3, 2)
+
+	result := Concat(first, second, third)
+	expected := []int16{1, 1, 1, 2, 2, 2, 2, 2, 3, 3}
+	if len(result) != len(expected) {
+		t.Fatalf("Expected %d samples, got %d", len(expected), len(result))
+	}
+	for i, v := range result {
+		if v != expected[i] {
+			t.Errorf("Expected sample %d to be %d, got %d", i, expected[i], v)
+		}
+	}
+}
+
+func TestConcatChannels(t *testing.T) {
+	result, err := ConcatChannels(2, createTestWaveform(1, 4), createTestWaveform(2, 6))
+	if err != nil {
+		t.Fatalf("Error in ConcatChannels: %v", err)
+	}
+	if len(result) != 10 {
+		t.Errorf("Expected 10 samples, got %d", len(result))
+	}
+
+	if _, err := ConcatChannels(2, createTestWaveform(1, 4), createTestWaveform(2, 5)); err == nil {
+		t.Error("Expected error when a length is not a multiple of the number of channels")
+	}
+}
+
+func TestChunk(t *testing.T) {
+	samples := createSineWave(440, 10000, 44100, 1000)
+
+	chunks := Chunk(samples, 300)
+	if len(chunks) != 4 {
+		t.Fatalf("Expected 4 chunks, got %d", len(chunks))
+	}
+	for i, chunk := range chunks {
+		expected := 300
+		if i == 3 {
+			expected = 100
+		}
+		if len(chunk) != expected {
+			t.Errorf("Expected chunk %d to have %d samples, got %d", i, expected, len(chunk))
+		}
+	}
+	for i, v := range Concat(chunks...) {
+		if v != samples[i] {
+			t.Fatalf("Expected the concatenated chunks to equal the input at index %d", i)
+		}
+	}
+
+	if chunks := Chunk(samples, 0); chunks != nil {
+		t.Error("Expected nil for a chunk size of 0")
+	}
+}
+
+func TestChunkPadded(t *testing.T) {
+	samples := createTestWaveform(1000, 1000)
+
+	chunks := ChunkPadded(samples, 300)
+	if len(chunks) != 4 {
+		t.Fatalf("Expected 4 chunks, got %d", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if len(chunk) != 300 {
+			t.Errorf("Expected chunk %d to have 300 samples, got %d", i, len(chunk))
+		}
+	}
+	combined := Concat(chunks...)
+	for i, v := range combined {
+		expected := int16(1000)
+		if i >= len(samples) {
+			expected = 0 // The padding of the last chunk
+		}
+		if v != expected {
+			t.Fatalf("Expected sample %d to be %d, got %d", i, expected, v)
+		}
+	}
+}
+
 func TestLowPassFilter(t *testing.T) {
 	samples := []int16{100, 200, 300, 400, 500}
 	filtered := LowPassFilter(samples, 44100, 1000) // Apply a low-pass filter with 1kHz cutoff
@@ -64,6 +473,94 @@ func TestLowPassFilter(t *testing.T) {
 	}
 }
 
+func TestLowPassFilterChannels(t *testing.T) {
+	// The left channel is silent, while the right channel is a constant signal
+	stereo := make([]int16, 2000)
+	for i := 1; i < len(stereo); i += 2 {
+		stereo[i] = 10000
+	}
+
+	filtered := LowPassFilterChannels(stereo, 44100, 1000, 2)
+	if len(filtered) != len(stereo) {
+		t.Fatalf("Expected %d samples, got %d", len(stereo), len(filtered))
+	}
+	for i := 0; i < len(filtered); i += 2 {
+		if filtered[i] != 0 {
+			t.Fatalf("Expected the left channel to stay silent, got %d at index %d", filtered[i], i)
+		}
+		if filtered[i+1] != 10000 {
+			t.Fatalf("Expected the right channel to stay at 10000, got %d at index %d", filtered[i+1], i+1)
+		}
+	}
+
+	// Filtering the interleaved samples as one channel mixes the channels together
+	if mixed := LowPassFilter(stereo, 44100, 1000); mixed[len(mixed)-2] == 0 {
+		t.Error("Expected filtering across channels to cross-contaminate the left channel")
+	}
+
+	if LowPassFilterChannels(stereo, 44100, 1000, 0) != nil {
+		t.Error("Expected nil for an invalid number of channels")
+	}
+	if filtered := LowPassFilter(nil, 44100, 1000); len(filtered) != 0 {
+		t.Errorf("Expected no samples for empty input, got %d", len(filtered))
+	}
+}
+
+func TestHighPassFilterDC(t *testing.T) {
+	samples := createTestWaveform(10000, 44100)
+	filtered := HighPassFilter(samples, 44100, 100) // Apply a high-pass filter with 100Hz cutoff
+
+	if len(filtered) != len(samples) {
+		t.Errorf("Expected filtered samples to have the same length, got %d and %d", len(filtered), len(samples))
+	}
+
+	// A constant DC signal should decay towards zero
+	if last := filtered[len(filtered)-1]; math.Abs(float64(last)) > 10 {
+		t.Errorf("Expected the DC signal to be removed, but the last sample is %d", last)
+	}
+}
+
+func TestHighPassFilterHighFrequency(t *testing.T) {
+	// Alternating values is the highest frequency that can be represented
+	samples := make([]int16, 1000)
+	for i := range samples {
+		if i%2 == 0 {
+			samples[i] = 10000
+		} else {
+			samples[i] = -10000
+		}
+	}
+	filtered := HighPassFilter(samples, 44100, 100)
+
+	// The high frequency signal should pass largely unattenuated
+	if peak := FindPeakAmplitude(filtered[500:]); peak < 9000 {
+		t.Errorf("Expected the high frequency signal to pass, but the peak amplitude is %d", peak)
+	}
+}
+
+func TestRemoveDCOffset(t *testing.T) {
+	// 10 full periods of a sine wave, offset by +500
+	sine := createSineWave(441, 8000, 44100, 1000)
+	samples := make([]int16, len(sine))
+	for i, v := range sine {
+		samples[i] = v + 500
+	}
+
+	result := RemoveDCOffset(samples)
+	sum := 0.0
+	for _, v := range result {
+		sum += float64(v)
+	}
+	if mean := sum / float64(len(result)); math.Abs(mean) > 1 {
+		t.Errorf("Expected the mean to be about 0, got %.2f", mean)
+	}
+	for i, v := range result {
+		if math.Abs(float64(v)-float64(sine[i])) > 2 {
+			t.Fatalf("Expected sample %d to be about %d, got %d", i, sine[i], v)
+		}
+	}
+}
+
 func TestNormalizeSamples(t *testing.T) {
 	samples := []int16{100, 200, -300}
 	targetPeak := int16(1000)
@@ -76,6 +573,92 @@ func TestNormalizeSamples(t *testing.T) {
 	}
 }
 
+func TestNormalizeSamplesErr(t *testing.T) {
+	if _, err := NormalizeSamplesErr(make([]int16, 100), 10000); !errors.Is(err, ErrSilentInput) {
+		t.Errorf("Expected ErrSilentInput for silent input, got %v", err)
+	}
+
+	normalized, err := NormalizeSamplesErr(createTestWaveform(5000, 10), 10000)
+	if err != nil {
+		t.Fatalf("Error in NormalizeSamplesErr: %v", err)
+	}
+	if peak := FindPeakAmplitude(normalized); peak != 10000 {
+		t.Errorf("Expected peak 10000, got %d", peak)
+	}
+}
+
+func TestNormalizeSamplesHeadroom(t *testing.T) {
+	samples := []int16{100, 200, -300}
+
+	for _, targetDBFS := range []float64{-1, -6, -20} {
+		normalized := NormalizeSamplesHeadroom(samples, targetDBFS)
+		peakDBFS := 20 * math.Log10(float64(FindPeakAmplitude(normalized))/math.MaxInt16)
+		if math.Abs(peakDBFS-targetDBFS) > 0.01 {
+			t.Errorf("Expected a peak at %.1f dBFS, got %.3f dBFS", targetDBFS, peakDBFS)
+		}
+	}
+}
+
+func TestApplyGainDB(t *testing.T) {
+	samples := []int16{1000, -2000, 3000}
+
+	// +6 dB roughly doubles the amplitude
+	amplified := ApplyGainDB(samples, 6)
+	for i, v := range amplified {
+		ratio := float64(v) / float64(samples[i])
+		if math.Abs(ratio-2) > 0.01 {
+			t.Errorf("Expected +6 dB to roughly double sample %d, got a ratio of %.3f", i, ratio)
+		}
+	}
+
+	// -Inf dB and very negative gains silence the signal
+	for _, gainDB := range []float64{math.Inf(-1), -200} {
+		for i, v := range ApplyGainDB(samples, gainDB) {
+			if v != 0 {
+				t.Errorf("Expected %.0f dB to silence sample %d, got %d", gainDB, i, v)
+			}
+		}
+	}
+
+	// Large gains are clamped instead of wrapping around
+	clamped := ApplyGainDB(samples, 40)
+	if clamped[0] != math.MaxInt16 || clamped[1] != math.MinInt16 {
+		t.Errorf("Expected large gains to be clamped, got %v", clamped)
+	}
+}
+
+func TestApplyGain(t *testing.T) {
+	samples := []int16{1000, -2000, 3000}
+	for i, v := range ApplyGain(samples, 0.5) {
+		if v != samples[i]/2 {
+			t.Errorf("Expected sample %d to be halved, got %d", i, v)
+		}
+	}
+}
+
+func TestInvertPolarity(t *testing.T) {
+	samples := createSineWave(440, 20000, 44100, 1000)
+	inverted := InvertPolarity(samples)
+
+	for i, v := range InvertPolarity(inverted) {
+		if v != samples[i] {
+			t.Fatalf("Expected inverting twice to return the original sample %d, got %d", samples[i], v)
+		}
+	}
+
+	sum, err := LinearSummation(samples, inverted)
+	if err != nil {
+		t.Fatalf("Error in LinearSummation: %v", err)
+	}
+	if peak := FindPeakAmplitude(sum); peak != 0 {
+		t.Errorf("Expected a signal summed with its inverted copy to be silent, got peak %d", peak)
+	}
+
+	if v := InvertPolarity([]int16{math.MinInt16})[0]; v != math.MaxInt16 {
+		t.Errorf("Expected math.MinInt16 to be inverted to math.MaxInt16, got %d", v)
+	}
+}
+
 func TestFindPeakAmplitude(t *testing.T) {
 	samples := []int16{100, 200, -300}
 	expectedPeak := int16(300)
@@ -86,6 +669,40 @@ func TestFindPeakAmplitude(t *testing.T) {
 	}
 }
 
+func TestHeadroom(t *testing.T) {
+	// A peak at half of full scale leaves roughly 6 dB of headroom
+	samples := []int16{100, -16384, 200}
+	headroom := Headroom(samples)
+	if math.Abs(headroom-6.02) > 0.01 {
+		t.Errorf("Expected about 6.02 dB of headroom, got %.2f", headroom)
+	}
+
+	// Full scale leaves no headroom
+	if headroom := Headroom([]int16{math.MaxInt16}); headroom != 0 {
+		t.Errorf("Expected 0 dB of headroom at full scale, got %.2f", headroom)
+	}
+
+	// Silence has unlimited headroom
+	if headroom := Headroom([]int16{0, 0, 0}); !math.IsInf(headroom, 1) {
+		t.Errorf("Expected +Inf headroom for silent input, got %.2f", headroom)
+	}
+}
+
+func TestFindPeakAmplitudeMinInt16(t *testing.T) {
+	samples := []int16{100, math.MinInt16, -300}
+	peak := FindPeakAmplitude(samples)
+
+	if peak != math.MaxInt16 {
+		t.Errorf("Expected peak amplitude %d, got %d", math.MaxInt16, peak)
+	}
+
+	// NormalizeSamples relies on the peak, so it should scale down rather than flip the signal
+	normalized := NormalizeSamples(samples, 16384)
+	if normalized[1] > -16000 || normalized[0] <= 0 {
+		t.Errorf("Expected the normalized samples to keep their sign and scale, got %v", normalized)
+	}
+}
+
 func TestAnalyzeHighestFrequency(t *testing.T) {
 	// Simple samples with alternating values for zero-crossing detection
 	samples := []int16{1000, -1000, 1000, -1000}
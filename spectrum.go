@@ -0,0 +1,271 @@
+package mixorama
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+// DefaultFrequencyThresholdDB is the threshold used by AnalyzeHighestFrequency, relative to the strongest frequency
+const DefaultFrequencyThresholdDB = -40.0
+
+// nextPowerOfTwo returns the smallest power of two that is greater than or equal to n
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// fft performs an in-place radix-2 Fast Fourier Transform. The length of x must be a power of two.
+func fft(x []complex128) {
+	n := len(x)
+
+	// Reorder the values in bit-reversed order
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	// Combine the transforms of increasing size
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				even := x[start+k]
+				odd := w * x[start+k+size/2]
+				x[start+k] = even + odd
+				x[start+k+size/2] = even - odd
+				w *= step
+			}
+		}
+	}
+}
+
+// ifft performs an in-place inverse Fast Fourier Transform, including the 1/n scaling.
+// The length of x must be a power of two.
+func ifft(x []complex128) {
+	for i := range x {
+		x[i] = cmplx.Conj(x[i])
+	}
+	fft(x)
+	scale := complex(1/float64(len(x)), 0)
+	for i := range x {
+		x[i] = cmplx.Conj(x[i]) * scale
+	}
+}
+
+// AnalyzeSpectrum returns the magnitude and the frequency of each bin of the spectrum of the samples.
+// The samples are Hann-windowed and zero-padded to a power of two before the FFT is performed.
+// The magnitudes are scaled so that a sine wave with amplitude A gives a peak of about A.
+func AnalyzeSpectrum(samples []int16, sampleRate int) ([]float64, []float64) {
+	l := len(samples)
+	if l == 0 {
+		return []float64{}, []float64{}
+	}
+
+	n := nextPowerOfTwo(l)
+	window := hannWindow(l)
+	windowSum := 0.0
+	x := make([]complex128, n)
+	for i := 0; i < l; i++ {
+		x[i] = complex(float64(samples[i])*window[i], 0)
+		windowSum += window[i]
+	}
+	if windowSum == 0 {
+		// A single sample can not be windowed, so use it as it is
+		x[0] = complex(float64(samples[0]), 0)
+		windowSum = 1
+	}
+
+	fft(x)
+
+	numBins := n/2 + 1
+	magnitudes := make([]float64, numBins)
+	frequencies := make([]float64, numBins)
+	for k := 0; k < numBins; k++ {
+		magnitudes[k] = 2 * cmplx.Abs(x[k]) / windowSum
+		frequencies[k] = float64(k) * float64(sampleRate) / float64(n)
+	}
+
+	return magnitudes, frequencies
+}
+
+// noiseFloorMarginDB is how far above the median magnitude of the spectrum a bin must be to count as a frequency
+// in the signal, rather than as noise
+const noiseFloorMarginDB = 20.0
+
+// AnalyzeHighestFrequencyThreshold returns the frequency of the highest spectrum bin with a magnitude
+// within thresholdDB of the strongest bin. A thresholdDB of -40 ignores frequencies that are more than 40 dB weaker.
+// Any DC offset is removed before the analysis, and bins that are less than noiseFloorMarginDB above the median
+// magnitude are treated as noise, so that broadband noise does not push the estimate towards the Nyquist frequency.
+func AnalyzeHighestFrequencyThreshold(samples []int16, sampleRate int, thresholdDB float64) float64 {
+	magnitudes, frequencies := AnalyzeSpectrum(RemoveDCOffset(samples), sampleRate)
+
+	strongest := 0.0
+	for _, magnitude := range magnitudes {
+		if magnitude > strongest {
+			strongest = magnitude
+		}
+	}
+	if strongest == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(magnitudes))
+	copy(sorted, magnitudes)
+	sort.Float64s(sorted)
+	noiseFloor := sorted[len(sorted)/2] * math.Pow(10, noiseFloorMarginDB/20)
+
+	// The strongest bin always counts, even if the noise floor is above it
+	threshold := math.Max(strongest*math.Pow(10, thresholdDB/20), math.Min(noiseFloor, strongest))
+	for k := len(magnitudes) - 1; k >= 0; k-- {
+		if magnitudes[k] >= threshold {
+			return frequencies[k]
+		}
+	}
+
+	return 0
+}
+
+// SpectralCentroid returns the magnitude-weighted mean frequency of the spectrum of the samples,
+// which is a measure of how bright the sound is. Silent or empty input returns 0.
+func SpectralCentroid(samples []int16, sampleRate int) float64 {
+	magnitudes, frequencies := AnalyzeSpectrum(samples, sampleRate)
+	weightedSum, magnitudeSum := 0.0, 0.0
+	for i, magnitude := range magnitudes {
+		weightedSum += magnitude * frequencies[i]
+		magnitudeSum += magnitude
+	}
+	if magnitudeSum == 0 {
+		return 0
+	}
+	return weightedSum / magnitudeSum
+}
+
+// DetectFrequencyMagnitude returns the magnitude of a single frequency in the samples, using the Goertzel algorithm,
+// which is much cheaper than a full FFT when only one frequency is of interest, such as when detecting DTMF tones.
+// The magnitude is scaled so that a sine wave with amplitude A at targetFreq gives about A.
+// Empty input or a sample rate that is not positive returns 0.
+func DetectFrequencyMagnitude(samples []int16, sampleRate int, targetFreq float64) float64 {
+	if len(samples) == 0 || sampleRate <= 0 {
+		return 0
+	}
+
+	omega := 2 * math.Pi * targetFreq / float64(sampleRate)
+	coefficient := 2 * math.Cos(omega)
+	s1, s2 := 0.0, 0.0
+	for _, sample := range samples {
+		s0 := float64(sample) + coefficient*s1 - s2
+		s2 = s1
+		s1 = s0
+	}
+
+	power := s1*s1 + s2*s2 - coefficient*s1*s2
+	if power < 0 {
+		// Avoid taking the square root of a tiny negative number caused by rounding
+		power = 0
+	}
+	return 2 * math.Sqrt(power) / float64(len(samples))
+}
+
+const (
+	// onsetFrameMs is the approximate length in milliseconds of each frame that DetectOnsets analyzes.
+	// The frames are a power of two samples long, and overlap by three quarters.
+	onsetFrameMs = 23
+	// onsetPeakFrames is how many frames on each side a spectral flux peak must be the largest within
+	onsetPeakFrames = 3
+	// onsetAverageFrames is how many frames before a spectral flux peak the adaptive threshold averages over
+	onsetAverageFrames = 16
+	// onsetThreshold is how much a spectral flux peak must exceed the local average, relative to the largest flux
+	onsetThreshold = 0.1
+)
+
+// DetectOnsets returns the sample indices where notes or beats start in the mono samples, using spectral flux:
+// the summed increase in magnitude of each frequency bin from one frame to the next.
+// A frame counts as an onset if its flux is the largest within onsetPeakFrames frames on each side,
+// and it exceeds the average flux of the preceding frames by a margin. The frames are centered on the
+// returned indices, so the onsets are accurate to within about a quarter of a frame, which is 256 samples at 44100 Hz.
+// Empty or silent input, or a sample rate that is not positive, returns no onsets.
+func DetectOnsets(samples []int16, sampleRate int) []int {
+	if len(samples) == 0 || sampleRate <= 0 {
+		return []int{}
+	}
+
+	frameSize := nextPowerOfTwo(onsetFrameMs * sampleRate / 1000)
+	if frameSize < 4 {
+		frameSize = 4
+	}
+	hopSize := frameSize / 4
+	window := hannWindow(frameSize)
+	numFrames := (len(samples)+hopSize-1)/hopSize + 1
+	numBins := frameSize/2 + 1
+	flux := make([]float64, numFrames)
+	previous := make([]float64, numBins)
+	x := make([]complex128, frameSize)
+	maxFlux := 0.0
+	for i := 0; i < numFrames; i++ {
+		// Center the frame on sample i*hopSize, with zeros outside of the samples
+		start := i*hopSize - frameSize/2
+		for j := range x {
+			x[j] = 0
+			if k := start + j; k >= 0 && k < len(samples) {
+				x[j] = complex(float64(samples[k])*window[j], 0)
+			}
+		}
+		fft(x)
+		for k := 0; k < numBins; k++ {
+			magnitude := cmplx.Abs(x[k])
+			if increase := magnitude - previous[k]; increase > 0 {
+				flux[i] += increase
+			}
+			previous[k] = magnitude
+		}
+		if flux[i] > maxFlux {
+			maxFlux = flux[i]
+		}
+	}
+	if maxFlux == 0 {
+		return []int{}
+	}
+
+	onsets := []int{}
+	for i := 0; i < numFrames; i++ {
+		isPeak := flux[i] > 0
+		for j := i - onsetPeakFrames; j <= i+onsetPeakFrames && isPeak; j++ {
+			if j < 0 || j >= numFrames || j == i {
+				continue
+			}
+			// Ties count as a peak only for the first frame, so that a flat top gives a single onset
+			if flux[j] > flux[i] || (j < i && flux[j] == flux[i]) {
+				isPeak = false
+			}
+		}
+		if !isPeak {
+			continue
+		}
+		sum, count := 0.0, 0
+		for j := i - onsetAverageFrames; j < i; j++ {
+			if j >= 0 {
+				sum += flux[j]
+				count++
+			}
+		}
+		average := 0.0
+		if count > 0 {
+			average = sum / float64(count)
+		}
+		if flux[i] >= average+onsetThreshold*maxFlux {
+			onsets = append(onsets, i*hopSize)
+		}
+	}
+	return onsets
+}
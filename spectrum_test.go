@@ -0,0 +1,153 @@
+package mixorama
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAnalyzeSpectrum(t *testing.T) {
+	sampleRate := 44100
+	samples := createSineWave(1000, 10000, sampleRate, 4096)
+	magnitudes, frequencies := AnalyzeSpectrum(samples, sampleRate)
+
+	if len(magnitudes) != len(frequencies) {
+		t.Fatalf("Expected as many magnitudes as frequencies, got %d and %d", len(magnitudes), len(frequencies))
+	}
+
+	peakBin := 0
+	for k, magnitude := range magnitudes {
+		if magnitude > magnitudes[peakBin] {
+			peakBin = k
+		}
+	}
+
+	binWidth := frequencies[1] - frequencies[0]
+	if math.Abs(frequencies[peakBin]-1000) > binWidth {
+		t.Errorf("Expected the peak to be within one bin (%.2f Hz) of 1000 Hz, got %.2f Hz", binWidth, frequencies[peakBin])
+	}
+	if math.Abs(magnitudes[peakBin]-10000) > 2000 {
+		t.Errorf("Expected a peak magnitude of about 10000, got %.2f", magnitudes[peakBin])
+	}
+}
+
+func TestAnalyzeSpectrumZeroPadding(t *testing.T) {
+	// 3000 is not a power of two, so the samples are zero-padded to 4096
+	magnitudes, frequencies := AnalyzeSpectrum(createSineWave(1000, 10000, 44100, 3000), 44100)
+	if len(magnitudes) != 4096/2+1 {
+		t.Errorf("Expected %d bins, got %d", 4096/2+1, len(magnitudes))
+	}
+	if frequencies[len(frequencies)-1] != 22050 {
+		t.Errorf("Expected the last bin to be at the Nyquist frequency, got %.2f", frequencies[len(frequencies)-1])
+	}
+}
+
+func TestAnalyzeHighestFrequencySine(t *testing.T) {
+	frequency := AnalyzeHighestFrequency(createSineWave(1000, 10000, 44100, 8192), 44100)
+	if math.Abs(frequency-1000) > 50 {
+		t.Errorf("Expected a highest frequency close to 1000 Hz, got %.2f", frequency)
+	}
+}
+
+func TestAnalyzeHighestFrequencyNoiseAndDC(t *testing.T) {
+	sampleRate := 44100
+	tone := createSineWave(1000, 8000, sampleRate, 8192)
+	noise := createNoise(3000, len(tone), 1)
+	samples := make([]int16, len(tone))
+	for i := range samples {
+		samples[i] = tone[i] + noise[i] + 10000
+	}
+
+	// Without the noise floor, the noise bins near the Nyquist frequency are within 40 dB of the tone
+	magnitudes, frequencies := AnalyzeSpectrum(noise, sampleRate)
+	strongestNoise := 0.0
+	for i, magnitude := range magnitudes {
+		if frequencies[i] > 15000 {
+			strongestNoise = math.Max(strongestNoise, magnitude)
+		}
+	}
+	if strongestNoise < 80 {
+		t.Fatalf("Expected the noise to be strong enough to affect a plain -40 dB threshold, got %.2f", strongestNoise)
+	}
+
+	frequency := AnalyzeHighestFrequency(samples, sampleRate)
+	if math.Abs(frequency-1000) > 50 {
+		t.Errorf("Expected a highest frequency close to 1000 Hz, got %.2f", frequency)
+	}
+}
+
+func TestSpectralCentroid(t *testing.T) {
+	sampleRate := 44100
+	low := SpectralCentroid(createSineWave(200, 10000, sampleRate, 8192), sampleRate)
+	high := SpectralCentroid(createSineWave(5000, 10000, sampleRate, 8192), sampleRate)
+
+	if low >= high {
+		t.Errorf("Expected the low tone to have a lower centroid than the high tone, got %.2f and %.2f", low, high)
+	}
+	if math.Abs(high-5000) > 100 {
+		t.Errorf("Expected the centroid of a 5000 Hz tone to be close to 5000 Hz, got %.2f", high)
+	}
+
+	// Two equally strong tones have their centroid in the middle
+	both, err := LinearSummation(createSineWave(1000, 8000, sampleRate, 8192), createSineWave(3000, 8000, sampleRate, 8192))
+	if err != nil {
+		t.Fatalf("Error in LinearSummation: %v", err)
+	}
+	if centroid := SpectralCentroid(both, sampleRate); math.Abs(centroid-2000) > 100 {
+		t.Errorf("Expected the centroid of two equal tones at 1000 and 3000 Hz to be about 2000 Hz, got %.2f", centroid)
+	}
+
+	if centroid := SpectralCentroid(make([]int16, 100), sampleRate); centroid != 0 {
+		t.Errorf("Expected the centroid of silence to be 0, got %.2f", centroid)
+	}
+}
+
+func TestDetectFrequencyMagnitude(t *testing.T) {
+	sampleRate := 8000
+	tone := createSineWave(697, 10000, sampleRate, sampleRate/10)
+
+	onTarget := DetectFrequencyMagnitude(tone, sampleRate, 697)
+	if math.Abs(onTarget-10000) > 500 {
+		t.Errorf("Expected a magnitude of about 10000 at the tone frequency, got %.2f", onTarget)
+	}
+
+	offTarget := DetectFrequencyMagnitude(createSineWave(1209, 10000, sampleRate, sampleRate/10), sampleRate, 697)
+	if offTarget > 500 {
+		t.Errorf("Expected a low magnitude for a tone at another frequency, got %.2f", offTarget)
+	}
+
+	if magnitude := DetectFrequencyMagnitude(nil, sampleRate, 697); magnitude != 0 {
+		t.Errorf("Expected 0 for empty input, got %.2f", magnitude)
+	}
+}
+
+func TestDetectOnsets(t *testing.T) {
+	sampleRate := 44100
+	expected := []int{11025, 33075, 55125, 77175}
+
+	// Silence with decaying tone bursts starting at the expected onsets
+	samples := make([]int16, 2*sampleRate)
+	burst := createSineWave(880, 10000, sampleRate, sampleRate/5)
+	for _, onset := range expected {
+		for i, sample := range burst {
+			decay := math.Exp(-float64(i) / float64(sampleRate/20))
+			samples[onset+i] += int16(float64(sample) * decay)
+		}
+	}
+
+	onsets := DetectOnsets(samples, sampleRate)
+	if len(onsets) != len(expected) {
+		t.Fatalf("Expected %d onsets, got %d: %v", len(expected), len(onsets), onsets)
+	}
+	for i, onset := range onsets {
+		if math.Abs(float64(onset-expected[i])) > 512 {
+			t.Errorf("Expected onset %d near sample %d, got %d", i+1, expected[i], onset)
+		}
+	}
+
+	if onsets := DetectOnsets(make([]int16, sampleRate), sampleRate); len(onsets) != 0 {
+		t.Errorf("Expected no onsets in silence, got %v", onsets)
+	}
+	if onsets := DetectOnsets(nil, sampleRate); len(onsets) != 0 {
+		t.Errorf("Expected no onsets for empty input, got %v", onsets)
+	}
+}
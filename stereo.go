@@ -0,0 +1,180 @@
+package mixorama
+
+import (
+	"errors"
+	"math"
+)
+
+// Pan applies constant-power panning to interleaved stereo samples.
+// pan ranges from -1.0 (full left) to 1.0 (full right), and 0 leaves the samples unchanged.
+// The gains follow the equal-power law, scaled so that the center position has unity gain,
+// which means that a channel is boosted by up to 3 dB when panning towards it.
+func Pan(samples []int16, pan float64) ([]int16, error) {
+	if len(samples)%2 != 0 {
+		return nil, errors.New("stereo samples must have an even length")
+	}
+
+	if pan < -1 {
+		pan = -1
+	} else if pan > 1 {
+		pan = 1
+	}
+
+	angle := (pan + 1) * math.Pi / 4
+	leftGain := math.Sqrt2 * math.Cos(angle)
+	rightGain := math.Sqrt2 * math.Sin(angle)
+
+	pannedSamples := make([]int16, len(samples))
+	for i := 0; i < len(samples); i += 2 {
+		pannedSamples[i] = clampToInt16(float64(samples[i]) * leftGain)
+		pannedSamples[i+1] = clampToInt16(float64(samples[i+1]) * rightGain)
+	}
+
+	return pannedSamples, nil
+}
+
+// SplitChannels splits interleaved samples into one slice per channel.
+// An incomplete frame at the end is dropped. If numChannels is not positive, nil is returned.
+func SplitChannels(interleaved []int16, numChannels int) [][]int16 {
+	if numChannels <= 0 {
+		return nil
+	}
+
+	numFrames := len(interleaved) / numChannels
+	channels := make([][]int16, numChannels)
+	for c := 0; c < numChannels; c++ {
+		channels[c] = make([]int16, numFrames)
+		for i := 0; i < numFrames; i++ {
+			channels[c][i] = interleaved[i*numChannels+c]
+		}
+	}
+
+	return channels
+}
+
+// MergeChannels interleaves the given channels into a single slice
+func MergeChannels(channels [][]int16) ([]int16, error) {
+	if len(channels) == 0 {
+		return nil, errors.New("no channels provided")
+	}
+
+	numChannels := len(channels)
+	numFrames := len(channels[0])
+	for _, channel := range channels {
+		if len(channel) != numFrames {
+			return nil, errors.New("mismatched channel lengths")
+		}
+	}
+
+	interleaved := make([]int16, numFrames*numChannels)
+	for c, channel := range channels {
+		for i, sample := range channel {
+			interleaved[i*numChannels+c] = sample
+		}
+	}
+
+	return interleaved, nil
+}
+
+// StereoToMono downmixes interleaved stereo samples to mono, by averaging the left and right channels.
+// The average is truncated towards zero.
+func StereoToMono(interleaved []int16) ([]int16, error) {
+	if len(interleaved)%2 != 0 {
+		return nil, errors.New("stereo samples must have an even length")
+	}
+
+	monoSamples := make([]int16, len(interleaved)/2)
+	for i := range monoSamples {
+		monoSamples[i] = int16((int32(interleaved[2*i]) + int32(interleaved[2*i+1])) / 2)
+	}
+
+	return monoSamples, nil
+}
+
+// StereoWiden turns mono samples into interleaved stereo with the Haas effect, by delaying the right channel
+// by delayMs milliseconds. Delays of about 1 to 30 ms widen the sound without being heard as an echo.
+// The output has the same number of frames as the input, so the end of the right channel is cut off.
+func StereoWiden(mono []int16, sampleRate int, delayMs float64) []int16 {
+	delaySamples := int(math.Round(delayMs * float64(sampleRate) / 1000))
+	if delaySamples < 0 {
+		delaySamples = 0
+	}
+
+	stereoSamples := make([]int16, len(mono)*2)
+	for i, sample := range mono {
+		stereoSamples[2*i] = sample
+		if i >= delaySamples {
+			stereoSamples[2*i+1] = mono[i-delaySamples]
+		}
+	}
+
+	return stereoSamples
+}
+
+// RemoveCenter cancels content that is panned to the center, such as lead vocals, by outputting (L - R) / 2
+// as mono samples. This is the classic "karaoke" trick. Anything that differs between the channels remains.
+func RemoveCenter(interleaved []int16) ([]int16, error) {
+	if len(interleaved)%2 != 0 {
+		return nil, errors.New("stereo samples must have an even length")
+	}
+
+	monoSamples := make([]int16, len(interleaved)/2)
+	for i := range monoSamples {
+		monoSamples[i] = int16((int32(interleaved[2*i]) - int32(interleaved[2*i+1])) / 2)
+	}
+
+	return monoSamples, nil
+}
+
+// EncodeMidSide converts interleaved stereo samples to mid (L + R) / 2 and side (L - R) / 2 channels,
+// so that the center and the stereo width can be processed independently. The halves are rounded down,
+// and an incomplete frame at the end is dropped. Use DecodeMidSide to convert back.
+func EncodeMidSide(interleaved []int16) (mid, side []int16) {
+	numFrames := len(interleaved) / 2
+	mid = make([]int16, numFrames)
+	side = make([]int16, numFrames)
+	for i := 0; i < numFrames; i++ {
+		l, r := int32(interleaved[2*i]), int32(interleaved[2*i+1])
+		// The shift rounds down, also for negative numbers
+		mid[i] = int16((l + r) >> 1)
+		side[i] = clampToInt16(float64((l - r) >> 1))
+	}
+	return mid, side
+}
+
+// DecodeMidSide converts mid and side channels back to interleaved stereo samples, where L = M + S and R = M - S.
+// The result is within one step of the samples that were given to EncodeMidSide.
+// If mid and side have different lengths, nil is returned.
+func DecodeMidSide(mid, side []int16) []int16 {
+	if len(mid) != len(side) {
+		return nil
+	}
+	interleaved := make([]int16, 2*len(mid))
+	for i := range mid {
+		m, s := float64(mid[i]), float64(side[i])
+		interleaved[2*i] = clampToInt16(m + s)
+		interleaved[2*i+1] = clampToInt16(m - s)
+	}
+	return interleaved
+}
+
+// StereoWidth changes the stereo width of interleaved stereo samples by scaling the side (L - R) / 2 component,
+// while keeping the mid (L + R) / 2 component. A width of 0 collapses the sound to mono, 1 leaves it unchanged
+// and values above 1 make it wider. A negative width is treated as 0. An incomplete frame at the end is copied as-is.
+func StereoWidth(interleaved []int16, width float64) []int16 {
+	if width < 0 {
+		width = 0
+	}
+
+	output := make([]int16, len(interleaved))
+	copy(output, interleaved)
+	for i := 0; i+1 < len(interleaved); i += 2 {
+		l, r := float64(interleaved[i]), float64(interleaved[i+1])
+		mid := (l + r) / 2
+		side := (l - r) / 2 * width
+		output[i] = clampToInt16(mid + side)
+		output[i+1] = clampToInt16(mid - side)
+	}
+
+	return output
+}
@@ -0,0 +1,247 @@
+package mixorama
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPanHardLeft(t *testing.T) {
+	samples := createTestWaveform(10000, 20)
+	panned, err := Pan(samples, -1)
+	if err != nil {
+		t.Fatalf("Error in Pan: %v", err)
+	}
+	if len(panned) != len(samples) {
+		t.Fatalf("Expected %d samples, got %d", len(samples), len(panned))
+	}
+
+	expectedLeft := int16(math.Round(10000 * math.Sqrt2))
+	for i := 0; i < len(panned); i += 2 {
+		if panned[i] != expectedLeft {
+			t.Errorf("Expected left sample %d to be %d, got %d", i, expectedLeft, panned[i])
+		}
+		if panned[i+1] != 0 {
+			t.Errorf("Expected right sample %d to be silent, got %d", i+1, panned[i+1])
+		}
+	}
+}
+
+func TestPanCenter(t *testing.T) {
+	samples := []int16{1000, -2000, 3000, -4000}
+	panned, err := Pan(samples, 0)
+	if err != nil {
+		t.Fatalf("Error in Pan: %v", err)
+	}
+	for i, v := range panned {
+		if v != samples[i] {
+			t.Errorf("Expected centered panning to leave sample %d unchanged, got %d", i, v)
+		}
+	}
+}
+
+func TestPanOddLength(t *testing.T) {
+	if _, err := Pan([]int16{1, 2, 3}, 0.5); err == nil {
+		t.Error("Expected error for an odd number of stereo samples")
+	}
+}
+
+func TestSplitAndMergeChannels(t *testing.T) {
+	interleaved := []int16{1, -1, 2, -2, 3, -3, 4, -4}
+
+	channels := SplitChannels(interleaved, 2)
+	if len(channels) != 2 {
+		t.Fatalf("Expected 2 channels, got %d", len(channels))
+	}
+	for i := 0; i < 4; i++ {
+		if channels[0][i] != int16(i+1) || channels[1][i] != -int16(i+1) {
+			t.Errorf("Unexpected samples in frame %d: left %d, right %d", i, channels[0][i], channels[1][i])
+		}
+	}
+
+	merged, err := MergeChannels(channels)
+	if err != nil {
+		t.Fatalf("Error in MergeChannels: %v", err)
+	}
+	if len(merged) != len(interleaved) {
+		t.Fatalf("Expected %d samples, got %d", len(interleaved), len(merged))
+	}
+	for i, v := range merged {
+		if v != interleaved[i] {
+			t.Errorf("Expected split and merge to be an identity at index %d: expected %d, got %d", i, interleaved[i], v)
+		}
+	}
+}
+
+func TestMergeChannelsMismatchedLengths(t *testing.T) {
+	if _, err := MergeChannels([][]int16{{1, 2, 3}, {1, 2}}); err == nil {
+		t.Error("Expected error for channels with different lengths")
+	}
+}
+
+func TestStereoToMono(t *testing.T) {
+	stereo := []int16{1000, 3000, -2000, 2000, math.MaxInt16, math.MaxInt16, math.MinInt16, math.MinInt16, 7, 8}
+	expected := []int16{2000, 0, math.MaxInt16, math.MinInt16, 7}
+
+	mono, err := StereoToMono(stereo)
+	if err != nil {
+		t.Fatalf("Error in StereoToMono: %v", err)
+	}
+	if len(mono) != len(expected) {
+		t.Fatalf("Expected %d samples, got %d", len(expected), len(mono))
+	}
+	for i, v := range mono {
+		if v != expected[i] {
+			t.Errorf("Expected mono sample %d to be %d, got %d", i, expected[i], v)
+		}
+	}
+
+	if _, err := StereoToMono([]int16{1, 2, 3}); err == nil {
+		t.Error("Expected error for odd-length input")
+	}
+}
+
+func TestStereoWiden(t *testing.T) {
+	mono := createSineWave(440, 10000, 44100, 1000)
+
+	// 10 ms at 44100 Hz is 441 samples
+	stereo := StereoWiden(mono, 44100, 10)
+	if len(stereo) != 2*len(mono) {
+		t.Fatalf("Expected %d samples, got %d", 2*len(mono), len(stereo))
+	}
+
+	channels := SplitChannels(stereo, 2)
+	left, right := channels[0], channels[1]
+	for i := range mono {
+		if left[i] != mono[i] {
+			t.Fatalf("Expected the left channel to equal the input at index %d", i)
+		}
+		expected := int16(0)
+		if i >= 441 {
+			expected = left[i-441]
+		}
+		if right[i] != expected {
+			t.Fatalf("Expected the right channel to be the left channel delayed by 441 samples, got %d at index %d instead of %d", right[i], i, expected)
+		}
+	}
+}
+
+func TestRemoveCenter(t *testing.T) {
+	center := createSineWave(440, 10000, 44100, 1000)
+	side := createSineWave(1000, 4000, 44100, 1000)
+
+	// The center content is in both channels, while the side content is only in the left channel
+	stereo, err := MergeChannels([][]int16{center, center})
+	if err != nil {
+		t.Fatalf("Error in MergeChannels: %v", err)
+	}
+	removed, err := RemoveCenter(stereo)
+	if err != nil {
+		t.Fatalf("Error in RemoveCenter: %v", err)
+	}
+	if len(removed) != len(center) {
+		t.Fatalf("Expected %d samples, got %d", len(center), len(removed))
+	}
+	if peak := FindPeakAmplitude(removed); peak != 0 {
+		t.Errorf("Expected center content to be cancelled, got peak %d", peak)
+	}
+
+	left, err := LinearSummation(center, side)
+	if err != nil {
+		t.Fatalf("Error in LinearSummation: %v", err)
+	}
+	stereo, err = MergeChannels([][]int16{left, center})
+	if err != nil {
+		t.Fatalf("Error in MergeChannels: %v", err)
+	}
+	removed, err = RemoveCenter(stereo)
+	if err != nil {
+		t.Fatalf("Error in RemoveCenter: %v", err)
+	}
+	for i, v := range removed {
+		if expected := side[i] / 2; v != expected {
+			t.Fatalf("Expected the side content to remain at half level, got %d at index %d instead of %d", v, i, expected)
+		}
+	}
+
+	if _, err := RemoveCenter([]int16{1, 2, 3}); err == nil {
+		t.Error("Expected error for odd-length input")
+	}
+}
+
+func TestMidSideRoundTrip(t *testing.T) {
+	left := createSineWave(440, 20000, 44100, 1000)
+	right := createNoise(20000, 1000, 3)
+	stereo, err := MergeChannels([][]int16{left, right})
+	if err != nil {
+		t.Fatalf("Error in MergeChannels: %v", err)
+	}
+	stereo = append(stereo, math.MaxInt16, math.MinInt16, math.MinInt16, math.MaxInt16, 3, -4, -5, -5)
+
+	mid, side := EncodeMidSide(stereo)
+	if len(mid) != len(stereo)/2 || len(side) != len(stereo)/2 {
+		t.Fatalf("Expected %d mid and side samples, got %d and %d", len(stereo)/2, len(mid), len(side))
+	}
+
+	decoded := DecodeMidSide(mid, side)
+	if len(decoded) != len(stereo) {
+		t.Fatalf("Expected %d decoded samples, got %d", len(stereo), len(decoded))
+	}
+	for i := range stereo {
+		if diff := int(decoded[i]) - int(stereo[i]); diff < -1 || diff > 1 {
+			t.Errorf("Expected %d at index %d within rounding, got %d", stereo[i], i, decoded[i])
+		}
+	}
+}
+
+func TestEncodeMidSideMono(t *testing.T) {
+	mono := createSineWave(440, 10000, 44100, 100)
+	stereo, err := MergeChannels([][]int16{mono, mono})
+	if err != nil {
+		t.Fatalf("Error in MergeChannels: %v", err)
+	}
+	mid, side := EncodeMidSide(stereo)
+	for i := range mono {
+		if mid[i] != mono[i] || side[i] != 0 {
+			t.Fatalf("Expected mid %d and side 0 at index %d, got %d and %d", mono[i], i, mid[i], side[i])
+		}
+	}
+	if DecodeMidSide(mid, side[:10]) != nil {
+		t.Error("Expected nil for mismatched mid and side lengths")
+	}
+}
+
+func TestStereoWidth(t *testing.T) {
+	left := createSineWave(440, 10000, 44100, 1000)
+	right := createNoise(10000, 1000, 4)
+	stereo, err := MergeChannels([][]int16{left, right})
+	if err != nil {
+		t.Fatalf("Error in MergeChannels: %v", err)
+	}
+
+	unchanged := StereoWidth(stereo, 1)
+	for i := range stereo {
+		if unchanged[i] != stereo[i] {
+			t.Fatalf("Expected width 1 to leave the samples unchanged, got %d instead of %d at index %d", unchanged[i], stereo[i], i)
+		}
+	}
+
+	mono := StereoWidth(stereo, 0)
+	for i := 0; i < len(mono); i += 2 {
+		if mono[i] != mono[i+1] {
+			t.Fatalf("Expected width 0 to give equal channels, got %d and %d at frame %d", mono[i], mono[i+1], i/2)
+		}
+	}
+
+	// Widening boosts the side component, while the mid component stays the same
+	wide := StereoWidth(stereo, 2)
+	mid, side := EncodeMidSide(stereo)
+	wideMid, wideSide := EncodeMidSide(wide)
+	if RMSLevel(wideSide) < 1.9*RMSLevel(side) {
+		t.Errorf("Expected the side level to be doubled, got %.2f (was %.2f)", RMSLevel(wideSide), RMSLevel(side))
+	}
+	for i := range mid {
+		if diff := int(wideMid[i]) - int(mid[i]); diff < -1 || diff > 1 {
+			t.Fatalf("Expected the mid component to stay the same, got %d instead of %d at index %d", wideMid[i], mid[i], i)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package mixorama
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// StreamMixer mixes raw 16-bit little-endian PCM from several readers as it is read,
+// by adding the samples together like LinearSummation. Sources that have ended are treated as silence.
+type StreamMixer struct {
+	sources []io.Reader
+	ended   []bool
+	buffer  []byte
+}
+
+// NewStreamMixer creates a StreamMixer that mixes the given sources of raw 16-bit little-endian PCM
+func NewStreamMixer(sources ...io.Reader) *StreamMixer {
+	return &StreamMixer{
+		sources: sources,
+		ended:   make([]bool, len(sources)),
+	}
+}
+
+// Read reads up to len(p)/2 samples from each source, mixes them and writes the result to p
+// as 16-bit little-endian PCM. The number of mixed samples is the number of samples read from the
+// source that provided the most. io.EOF is returned when all sources have ended.
+// If a source returns an error, the other sources are still read, so that they stay in step,
+// and the mix of the data that was read is returned together with the first error.
+func (m *StreamMixer) Read(p []byte) (int, error) {
+	numSamples := len(p) / 2
+	if numSamples == 0 {
+		return 0, errors.New("buffer must have room for at least one sample")
+	}
+	if cap(m.buffer) < numSamples*2 {
+		m.buffer = make([]byte, numSamples*2)
+	}
+	buffer := m.buffer[:numSamples*2]
+
+	chunks := make([][]int16, 0, len(m.sources))
+	mixedSamples := 0
+	var firstErr error
+	for i, source := range m.sources {
+		if m.ended[i] {
+			continue
+		}
+		n, err := io.ReadFull(source, buffer)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			m.ended[i] = true
+		} else if err != nil && firstErr == nil {
+			firstErr = err
+		}
+
+		// A trailing odd byte can not be a complete sample, and is dropped
+		chunk := make([]int16, numSamples)
+		for j := 0; j < n/2; j++ {
+			chunk[j] = int16(binary.LittleEndian.Uint16(buffer[2*j:]))
+		}
+		chunks = append(chunks, chunk)
+		if n/2 > mixedSamples {
+			mixedSamples = n / 2
+		}
+	}
+
+	if mixedSamples == 0 {
+		if firstErr != nil {
+			return 0, firstErr
+		}
+		return 0, io.EOF
+	}
+
+	mixed := make([]int16, mixedSamples)
+	linearSummationRange(chunks, mixed, 0, mixedSamples)
+	for j, sample := range mixed {
+		binary.LittleEndian.PutUint16(p[2*j:], uint16(sample))
+	}
+
+	return mixedSamples * 2, firstErr
+}
@@ -0,0 +1,83 @@
+package mixorama
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+// encodePCM returns the samples as raw 16-bit little-endian PCM
+func encodePCM(samples []int16) []byte {
+	data := make([]byte, len(samples)*2)
+	for i, sample := range samples {
+		binary.LittleEndian.PutUint16(data[2*i:], uint16(sample))
+	}
+	return data
+}
+
+func TestStreamMixer(t *testing.T) {
+	track1 := createSineWave(440, 20000, 44100, 1000)
+	track2 := createSineWave(660, 20000, 44100, 777)
+
+	mixer := NewStreamMixer(bytes.NewReader(encodePCM(track1)), bytes.NewReader(encodePCM(track2)))
+
+	var streamed []int16
+	p := make([]byte, 100)
+	for {
+		n, err := mixer.Read(p)
+		for i := 0; i < n/2; i++ {
+			streamed = append(streamed, int16(binary.LittleEndian.Uint16(p[2*i:])))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Error while reading from the StreamMixer: %v", err)
+		}
+	}
+
+	expected, err := LinearSummationPadded(track1, track2)
+	if err != nil {
+		t.Fatalf("Error in LinearSummationPadded: %v", err)
+	}
+	if len(streamed) != len(expected) {
+		t.Fatalf("Expected %d streamed samples, got %d", len(expected), len(streamed))
+	}
+	for i, v := range streamed {
+		if v != expected[i] {
+			t.Fatalf("Expected streamed sample %d to be %d, got %d", i, expected[i], v)
+		}
+	}
+}
+
+func TestStreamMixerSmallBuffer(t *testing.T) {
+	mixer := NewStreamMixer(bytes.NewReader(encodePCM([]int16{1, 2})))
+	if _, err := mixer.Read(make([]byte, 1)); err == nil {
+		t.Error("Expected error for a buffer that can not hold a sample")
+	}
+}
+
+func TestStreamMixerSourceError(t *testing.T) {
+	errBroken := errors.New("broken source")
+	broken := io.MultiReader(bytes.NewReader(encodePCM([]int16{100, 200, 300})), iotest.ErrReader(errBroken))
+	healthy := bytes.NewReader(encodePCM(createTestWaveform(1000, 10)))
+
+	mixer := NewStreamMixer(broken, healthy)
+	p := make([]byte, 20)
+	n, err := mixer.Read(p)
+	if !errors.Is(err, errBroken) {
+		t.Errorf("Expected the error of the broken source, got %v", err)
+	}
+	if n != 20 {
+		t.Fatalf("Expected the partial mix of 20 bytes to be returned, got %d", n)
+	}
+	expected := []int16{1100, 1200, 1300, 1000, 1000, 1000, 1000, 1000, 1000, 1000}
+	for i, v := range expected {
+		if sample := int16(binary.LittleEndian.Uint16(p[2*i:])); sample != v {
+			t.Errorf("Expected mixed sample %d to be %d, got %d", i, v, sample)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package mixorama
+
+import "math"
+
+// timeStretchFrameMs is the length of the frames that TimeStretch splits the samples into
+const timeStretchFrameMs = 40
+
+// TimeStretch changes the tempo of mono samples without changing the pitch, using WSOLA (waveform similarity
+// overlap-add). A factor of 0.5 plays at half speed, with twice as many samples, and 2.0 plays twice as fast.
+// Each frame is taken from near its nominal input position, where it best lines up with the previous frame,
+// which avoids the phase cancellation of plain overlap-add. If factor or sampleRate is not positive, nil is returned.
+func TimeStretch(samples []int16, sampleRate int, factor float64) []int16 {
+	if factor <= 0 || sampleRate <= 0 {
+		return nil
+	}
+
+	l := len(samples)
+	outLength := int(float64(l) / factor)
+	if l == 0 || outLength == 0 {
+		return make([]int16, outLength)
+	}
+
+	frameSize := sampleRate * timeStretchFrameMs / 1000
+	if frameSize < 4 {
+		frameSize = 4
+	}
+	frameSize -= frameSize % 2
+	hopSize := frameSize / 2
+	tolerance := hopSize / 2
+	window := hannWindow(frameSize)
+
+	// at returns the sample at the given position, or 0 outside of the samples
+	at := func(i int) float64 {
+		if i < 0 || i >= l {
+			return 0
+		}
+		return float64(samples[i])
+	}
+
+	output := make([]float64, outLength+frameSize)
+	windowSum := make([]float64, outLength+frameSize)
+	previous := 0
+	for k := 0; k*hopSize < outLength; k++ {
+		position := 0
+		if k > 0 {
+			// Find the frame near the nominal position that best continues the previous frame
+			nominal := int(math.Round(float64(k*hopSize) * factor))
+			natural := previous + hopSize
+			bestCorrelation := math.Inf(-1)
+			for candidate := nominal - tolerance; candidate <= nominal+tolerance; candidate++ {
+				correlation := 0.0
+				for i := 0; i < hopSize; i++ {
+					correlation += at(natural+i) * at(candidate+i)
+				}
+				if correlation > bestCorrelation {
+					bestCorrelation = correlation
+					position = candidate
+				}
+			}
+		}
+
+		start := k * hopSize
+		for i := 0; i < frameSize; i++ {
+			output[start+i] += at(position+i) * window[i]
+			windowSum[start+i] += window[i]
+		}
+		previous = position
+	}
+
+	stretched := make([]int16, outLength)
+	for i := 0; i < outLength; i++ {
+		if windowSum[i] > 1e-9 {
+			stretched[i] = clampToInt16(output[i] / windowSum[i])
+		}
+	}
+
+	return stretched
+}
@@ -0,0 +1,41 @@
+package mixorama
+
+import (
+	"math"
+	"testing"
+)
+
+// dominantFrequency returns the frequency of the strongest bin in the spectrum
+func dominantFrequency(samples []int16, sampleRate int) float64 {
+	magnitudes, frequencies := AnalyzeSpectrum(samples, sampleRate)
+	strongest := 0
+	for i, magnitude := range magnitudes {
+		if magnitude > magnitudes[strongest] {
+			strongest = i
+		}
+	}
+	return frequencies[strongest]
+}
+
+func TestTimeStretch(t *testing.T) {
+	sampleRate := 44100
+	samples := createSineWave(440, 10000, sampleRate, sampleRate)
+
+	for _, factor := range []float64{0.5, 2} {
+		stretched := TimeStretch(samples, sampleRate, factor)
+		expected := int(float64(len(samples)) / factor)
+		if len(stretched) != expected {
+			t.Fatalf("Expected %d samples for factor %.1f, got %d", expected, factor, len(stretched))
+		}
+		if peak := FindPeakAmplitude(stretched); peak < 9000 || peak > 11000 {
+			t.Errorf("Expected the amplitude to stay at about 10000 for factor %.1f, got peak %d", factor, peak)
+		}
+		if frequency := dominantFrequency(stretched, sampleRate); math.Abs(frequency-440) > 5 {
+			t.Errorf("Expected the dominant frequency to stay at 440 Hz for factor %.1f, got %.2f Hz", factor, frequency)
+		}
+	}
+
+	if TimeStretch(samples, sampleRate, 0) != nil {
+		t.Error("Expected nil for a factor of 0")
+	}
+}
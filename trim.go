@@ -0,0 +1,53 @@
+package mixorama
+
+// isQuiet returns true if the absolute amplitude of the sample is below the threshold
+func isQuiet(sample, threshold int16) bool {
+	return abs(int(sample)) < int(threshold)
+}
+
+// abs returns the absolute value of an int
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// isQuietFrame returns true if all samples of the interleaved frame that starts at index i are below the threshold
+func isQuietFrame(samples []int16, i, numChannels int, threshold int16) bool {
+	for c := 0; c < numChannels; c++ {
+		if !isQuiet(samples[i+c], threshold) {
+			return false
+		}
+	}
+	return true
+}
+
+// TrimSilenceEdges removes the leading and trailing frames of the interleaved samples where the absolute amplitude
+// of every channel is below the threshold. Whole frames are removed, so that the channels stay in order.
+// If all frames are below the threshold, an empty slice is returned. If numChannels is not positive, nil is returned.
+func TrimSilenceEdges(samples []int16, threshold int16, numChannels int) []int16 {
+	if numChannels < 1 {
+		return nil
+	}
+	// Ignore a trailing partial frame
+	l := len(samples) - len(samples)%numChannels
+	start := 0
+	for start < l && isQuietFrame(samples, start, numChannels, threshold) {
+		start += numChannels
+	}
+	end := l
+	for end > start && isQuietFrame(samples, end-numChannels, numChannels, threshold) {
+		end -= numChannels
+	}
+	trimmed := make([]int16, end-start)
+	copy(trimmed, samples[start:end])
+	return trimmed
+}
+
+// TrimSilence removes the leading and trailing mono samples whose absolute amplitude is below the threshold.
+// Quiet samples between the first and the last loud sample are kept.
+// If all samples are below the threshold, an empty slice is returned.
+func TrimSilence(samples []int16, threshold int16) []int16 {
+	return TrimSilenceEdges(samples, threshold, 1)
+}
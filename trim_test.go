@@ -0,0 +1,51 @@
+package mixorama
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTrimSilenceEdges(t *testing.T) {
+	// Interleaved stereo, where the first loud sample is in the right channel of the second frame
+	samples := []int16{0, 0, 0, 500, 600, 700, 800, 0, 0, 0}
+
+	trimmed := TrimSilenceEdges(samples, 10, 2)
+	expected := []int16{0, 500, 600, 700, 800, 0}
+	if !slices.Equal(trimmed, expected) {
+		t.Errorf("Expected %v, got %v", expected, trimmed)
+	}
+
+	if trimmed := TrimSilenceEdges(make([]int16, 100), 10, 2); len(trimmed) != 0 {
+		t.Errorf("Expected silent input to be trimmed to nothing, got %d samples", len(trimmed))
+	}
+	if trimmed := TrimSilenceEdges(samples, 10, 0); trimmed != nil {
+		t.Errorf("Expected nil for 0 channels, got %v", trimmed)
+	}
+}
+
+func TestTrimSilence(t *testing.T) {
+	first := createTestWaveform(8000, 300)
+	second := createTestWaveform(-8000, 300)
+	gap := make([]int16, 2000)
+
+	var samples []int16
+	samples = append(samples, make([]int16, 2000)...)
+	samples = append(samples, first...)
+	samples = append(samples, gap...)
+	samples = append(samples, second...)
+	samples = append(samples, make([]int16, 700)...)
+
+	// The silence between the first and the second part must be kept
+	trimmed := TrimSilence(samples, 10)
+	expected := len(first) + len(gap) + len(second)
+	if len(trimmed) != expected {
+		t.Fatalf("Expected %d samples after trimming, got %d", expected, len(trimmed))
+	}
+	if trimmed[0] != 8000 || trimmed[len(trimmed)-1] != -8000 {
+		t.Errorf("Expected the content to be preserved, got %d at the start and %d at the end", trimmed[0], trimmed[len(trimmed)-1])
+	}
+
+	if trimmed := TrimSilence(make([]int16, 100), 10); len(trimmed) != 0 {
+		t.Errorf("Expected silent input to be trimmed to nothing, got %d samples", len(trimmed))
+	}
+}
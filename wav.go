@@ -0,0 +1,319 @@
+package mixorama
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/go-audio/audio"
+	"github.com/go-audio/wav"
+)
+
+// writeSeekBuffer is an in-memory io.WriteSeeker, used for encoding .wav data for writers that can not seek
+type writeSeekBuffer struct {
+	data     []byte
+	position int
+}
+
+// Write writes p at the current position, growing the buffer as needed
+func (b *writeSeekBuffer) Write(p []byte) (int, error) {
+	end := b.position + len(p)
+	if end > len(b.data) {
+		b.data = append(b.data, make([]byte, end-len(b.data))...)
+	}
+	copy(b.data[b.position:], p)
+	b.position = end
+	return len(p), nil
+}
+
+// Seek sets the position for the next write
+func (b *writeSeekBuffer) Seek(offset int64, whence int) (int64, error) {
+	var position int64
+	switch whence {
+	case io.SeekStart:
+		position = offset
+	case io.SeekCurrent:
+		position = int64(b.position) + offset
+	case io.SeekEnd:
+		position = int64(len(b.data)) + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if position < 0 {
+		return 0, errors.New("negative position")
+	}
+	b.position = int(position)
+	return position, nil
+}
+
+// WavReader reads the samples of a .wav file chunk by chunk, without loading the entire file into memory
+type WavReader struct {
+	f           *os.File
+	decoder     *wav.Decoder
+	numChannels int
+	sampleRate  int
+	bitDepth    int
+}
+
+// NewWavReader opens a .wav file for reading in chunks. The reader must be closed after use.
+func NewWavReader(filename string) (*WavReader, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := wav.NewDecoder(f)
+	if err := decoder.FwdToPCM(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if decoder.NumChans == 0 {
+		f.Close()
+		return nil, errors.New("invalid number of channels")
+	}
+
+	return &WavReader{
+		f:           f,
+		decoder:     decoder,
+		numChannels: int(decoder.NumChans),
+		sampleRate:  int(decoder.SampleRate),
+		bitDepth:    int(decoder.BitDepth),
+	}, nil
+}
+
+// SampleRate returns the sample rate of the .wav file
+func (r *WavReader) SampleRate() int {
+	return r.sampleRate
+}
+
+// ReadChunk reads up to n frames (one sample per channel) and returns them in the same format as LoadWav,
+// which means that mono files are converted to stereo. io.EOF is returned when there are no more samples.
+func (r *WavReader) ReadChunk(n int) ([]int16, error) {
+	if n <= 0 {
+		return nil, errors.New("chunk size must be positive")
+	}
+
+	intBuffer := &audio.IntBuffer{Data: make([]int, n*r.numChannels)}
+	read, err := r.decoder.PCMBuffer(intBuffer)
+	if err != nil {
+		return nil, err
+	}
+	if read == 0 {
+		return nil, io.EOF
+	}
+
+	if r.numChannels == 1 {
+		// Convert mono to stereo by duplicating the mono channel
+		stereoSamples := make([]int16, read*2)
+		for i := 0; i < read; i++ {
+			monoSample := wavSampleToInt16(intBuffer.Data[i], r.bitDepth)
+			stereoSamples[2*i] = monoSample   // Left channel
+			stereoSamples[2*i+1] = monoSample // Right channel
+		}
+		return stereoSamples, nil
+	}
+
+	samples := make([]int16, read)
+	for i := 0; i < read; i++ {
+		samples[i] = wavSampleToInt16(intBuffer.Data[i], r.bitDepth)
+	}
+	return samples, nil
+}
+
+// Close closes the underlying file
+func (r *WavReader) Close() error {
+	return r.f.Close()
+}
+
+// peakChunkFrames is the number of frames that FindPeakAcrossFiles reads at a time
+const peakChunkFrames = 65536
+
+// FindPeakAcrossFiles returns the largest peak amplitude of all the .wav files, like FindPeakAmplitude.
+// The files are read chunk by chunk with a WavReader, so only one chunk is held in memory at a time.
+func FindPeakAcrossFiles(files []string) (int16, error) {
+	if len(files) == 0 {
+		return 0, errors.New("no input files provided")
+	}
+
+	peak := int16(0)
+	for _, file := range files {
+		filePeak, err := findPeakInFile(file)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		peak = max(peak, filePeak)
+	}
+	return peak, nil
+}
+
+// findPeakInFile returns the peak amplitude of a .wav file, by reading it chunk by chunk
+func findPeakInFile(filename string) (int16, error) {
+	reader, err := NewWavReader(filename)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	peak := int16(0)
+	for {
+		chunk, err := reader.ReadChunk(peakChunkFrames)
+		if err == io.EOF {
+			return peak, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		peak = max(peak, FindPeakAmplitude(chunk))
+	}
+}
+
+// RepairWav fixes the RIFF and data chunk sizes of a .wav file that was not properly finalized,
+// for instance by a recorder that crashed while writing. If the data chunk size is 0 or points
+// beyond the end of the file, it is recomputed from the file length. The header is rewritten in place.
+func RepairWav(filename string) error {
+	f, err := os.OpenFile(filename, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	fileSize := info.Size()
+
+	header := make([]byte, 12)
+	if _, err := f.ReadAt(header, 0); err != nil {
+		return errors.New("file is too short to be a wav file")
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return errors.New("not a RIFF/WAVE file")
+	}
+
+	// Walk the chunks until the data chunk is found, keeping track of the block alignment from the fmt chunk
+	blockAlign := int64(0)
+	offset := int64(12)
+	chunkHeader := make([]byte, 8)
+	for {
+		if offset+8 > fileSize {
+			return errors.New("no data chunk found")
+		}
+		if _, err := f.ReadAt(chunkHeader, offset); err != nil {
+			return err
+		}
+		chunkID := string(chunkHeader[0:4])
+		chunkSize := int64(binary.LittleEndian.Uint32(chunkHeader[4:8]))
+
+		if chunkID == "data" {
+			break
+		}
+
+		if chunkID == "fmt " {
+			if chunkSize < 16 || offset+8+16 > fileSize {
+				return errors.New("invalid fmt chunk")
+			}
+			fmtChunk := make([]byte, 16)
+			if _, err := f.ReadAt(fmtChunk, offset+8); err != nil {
+				return err
+			}
+			blockAlign = int64(binary.LittleEndian.Uint16(fmtChunk[12:14]))
+		}
+
+		// Chunks are padded to an even number of bytes
+		offset += 8 + chunkSize + chunkSize%2
+	}
+
+	if blockAlign == 0 {
+		return errors.New("no valid fmt chunk found before the data chunk")
+	}
+
+	dataOffset := offset + 8
+	dataSize := int64(binary.LittleEndian.Uint32(chunkHeader[4:8]))
+	riffSize := fileSize - 8
+
+	if dataSize == 0 || dataOffset+dataSize > fileSize {
+		// Use the rest of the file, dropping any incomplete sample frame at the end
+		dataSize = fileSize - dataOffset
+		dataSize -= dataSize % blockAlign
+		riffSize = dataOffset + dataSize - 8
+
+		sizeField := make([]byte, 4)
+		binary.LittleEndian.PutUint32(sizeField, uint32(dataSize))
+		if _, err := f.WriteAt(sizeField, offset+4); err != nil {
+			return err
+		}
+	}
+
+	sizeField := make([]byte, 4)
+	binary.LittleEndian.PutUint32(sizeField, uint32(riffSize))
+	if _, err := f.WriteAt(sizeField, 4); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DurationOfFile returns the playback duration of a .wav file.
+// Only the headers are read, the samples are not decoded.
+func DurationOfFile(filename string) (time.Duration, error) {
+	sampleRate, _, _, numFrames, err := InspectWav(filename)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(int64(numFrames) * int64(time.Second) / int64(sampleRate)), nil
+}
+
+// InspectWav returns the format and the number of frames of a .wav file, by reading only the fmt and data
+// chunk headers, without decoding any samples. This is much faster than loading the file.
+func InspectWav(filename string) (sampleRate, numChannels, bitDepth int, numFrames int, err error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	defer f.Close()
+
+	decoder := wav.NewDecoder(f)
+	if err := decoder.FwdToPCM(); err != nil {
+		return 0, 0, 0, 0, err
+	}
+	frameSize := int64(decoder.NumChans) * int64(decoder.BitDepth) / 8
+	if frameSize == 0 || decoder.SampleRate == 0 {
+		return 0, 0, 0, 0, errors.New("invalid format")
+	}
+	numFrames = int(decoder.PCMLen() / frameSize)
+	return int(decoder.SampleRate), int(decoder.NumChans), int(decoder.BitDepth), numFrames, nil
+}
+
+// ValidateMixInputs checks that all the .wav files can be read, and that they have the same sample rate and number
+// of channels as the first file, so that problems can be reported before any mixing is done. Only the headers are
+// read. The returned error lists every file that could not be read or does not match, with its format.
+func ValidateMixInputs(files []string) error {
+	if len(files) == 0 {
+		return errors.New("no input files provided")
+	}
+
+	firstRate, firstChannels, _, _, err := InspectWav(files[0])
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", files[0], err)
+	}
+
+	var errs []error
+	for _, file := range files[1:] {
+		sampleRate, numChannels, _, _, err := InspectWav(file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read %s: %w", file, err))
+			continue
+		}
+		if sampleRate != firstRate {
+			errs = append(errs, fmt.Errorf("%s has a sample rate of %d Hz, but %s has %d Hz", file, sampleRate, files[0], firstRate))
+		}
+		if numChannels != firstChannels {
+			errs = append(errs, fmt.Errorf("%s has %d channels, but %s has %d", file, numChannels, files[0], firstChannels))
+		}
+	}
+	return errors.Join(errs...)
+}
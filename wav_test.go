@@ -0,0 +1,297 @@
+package mixorama
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWavReader(t *testing.T) {
+	expected, expectedSampleRate, err := LoadWav("test.wav")
+	if err != nil {
+		t.Fatalf("Failed to load test.wav: %v", err)
+	}
+
+	reader, err := NewWavReader("test.wav")
+	if err != nil {
+		t.Fatalf("Failed to open test.wav: %v", err)
+	}
+	defer reader.Close()
+
+	if reader.SampleRate() != expectedSampleRate {
+		t.Errorf("Expected sample rate %d, got %d", expectedSampleRate, reader.SampleRate())
+	}
+
+	var samples []int16
+	for {
+		chunk, err := reader.ReadChunk(1000)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read chunk: %v", err)
+		}
+		samples = append(samples, chunk...)
+	}
+
+	if len(samples) != len(expected) {
+		t.Fatalf("Expected %d samples, got %d", len(expected), len(samples))
+	}
+	for i, v := range samples {
+		if v != expected[i] {
+			t.Fatalf("Expected sample %d to be %d, got %d", i, expected[i], v)
+		}
+	}
+}
+
+func TestRepairWav(t *testing.T) {
+	samples := []int16{1000, -1000, 2000, -2000, 3000, -3000}
+	filename := "test_repair.wav"
+	defer os.Remove(filename) // Cleanup after test
+
+	if err := SaveWav(filename, samples, 44100); err != nil {
+		t.Fatalf("Failed to save WAV file: %v", err)
+	}
+
+	// Simulate a recorder that crashed before finalizing the header
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Failed to read WAV file: %v", err)
+	}
+	binary.LittleEndian.PutUint32(data[4:8], 0)
+	binary.LittleEndian.PutUint32(data[40:44], 0)
+	if err := os.WriteFile(filename, data, 0o644); err != nil {
+		t.Fatalf("Failed to write WAV file: %v", err)
+	}
+
+	if err := RepairWav(filename); err != nil {
+		t.Fatalf("Failed to repair WAV file: %v", err)
+	}
+
+	repaired, sampleRate, err := LoadWav(filename)
+	if err != nil {
+		t.Fatalf("Failed to load repaired WAV file: %v", err)
+	}
+	if sampleRate != 44100 {
+		t.Errorf("Expected sample rate 44100, got %d", sampleRate)
+	}
+	// SaveWav writes mono, which LoadWav returns as stereo
+	if len(repaired) != 2*len(samples) {
+		t.Fatalf("Expected %d samples after repair, got %d", 2*len(samples), len(repaired))
+	}
+	for i, sample := range samples {
+		if repaired[2*i] != sample {
+			t.Errorf("Expected sample %d to be %d, got %d", i, sample, repaired[2*i])
+		}
+	}
+}
+
+func TestRepairWavInvalidFile(t *testing.T) {
+	filename := "test_repair_invalid.wav"
+	defer os.Remove(filename) // Cleanup after test
+
+	if err := os.WriteFile(filename, []byte("not a wav file at all"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := RepairWav(filename); err == nil {
+		t.Error("Expected error when repairing a file that is not a wav file")
+	}
+}
+
+func TestEncodeDecodeWav(t *testing.T) {
+	// Interleaved stereo samples
+	samples := []int16{1000, -1000, 2000, -2000, 32767, -32768}
+
+	var buffer bytes.Buffer
+	if err := EncodeWav(&buffer, samples, 48000, 2); err != nil {
+		t.Fatalf("Failed to encode WAV data: %v", err)
+	}
+
+	decoded, sampleRate, err := DecodeWav(&buffer)
+	if err != nil {
+		t.Fatalf("Failed to decode WAV data: %v", err)
+	}
+	if sampleRate != 48000 {
+		t.Errorf("Expected sample rate 48000, got %d", sampleRate)
+	}
+	if len(decoded) != len(samples) {
+		t.Fatalf("Expected %d samples, got %d", len(samples), len(decoded))
+	}
+	for i, v := range decoded {
+		if v != samples[i] {
+			t.Errorf("Expected sample %d to be %d, got %d", i, samples[i], v)
+		}
+	}
+}
+
+func TestDurationOfFile(t *testing.T) {
+	// test.wav has 21812 mono frames at 44100 Hz
+	duration, err := DurationOfFile("test.wav")
+	if err != nil {
+		t.Fatalf("Failed to get the duration of test.wav: %v", err)
+	}
+	expected := time.Duration(21812 * int64(time.Second) / 44100)
+	if duration != expected {
+		t.Errorf("Expected a duration of %v, got %v", expected, duration)
+	}
+
+	// The duration of the decoded samples should match
+	samples, sampleRate, err := LoadWav("test.wav")
+	if err != nil {
+		t.Fatalf("Failed to load test.wav: %v", err)
+	}
+	if d := Duration(samples, sampleRate, 2); d != expected {
+		t.Errorf("Expected the duration of the samples to be %v, got %v", expected, d)
+	}
+
+	if _, err := DurationOfFile("nonexistent.wav"); err == nil {
+		t.Error("Expected error for a missing file")
+	}
+}
+
+func TestValidateMixInputs(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.wav")
+	same := filepath.Join(dir, "same.wav")
+	otherRate := filepath.Join(dir, "other_rate.wav")
+	otherChannels := filepath.Join(dir, "other_channels.wav")
+
+	if err := SaveWav(first, createTestWaveform(1000, 100), 44100); err != nil {
+		t.Fatalf("Failed to save WAV file: %v", err)
+	}
+	if err := SaveWav(same, createTestWaveform(1000, 50), 44100); err != nil {
+		t.Fatalf("Failed to save WAV file: %v", err)
+	}
+	if err := SaveWav(otherRate, createTestWaveform(1000, 100), 48000); err != nil {
+		t.Fatalf("Failed to save WAV file: %v", err)
+	}
+	if err := saveWav(otherChannels, createTestWaveform(1000, 100), 44100, 2); err != nil {
+		t.Fatalf("Failed to save WAV file: %v", err)
+	}
+
+	if err := ValidateMixInputs([]string{first, same}); err != nil {
+		t.Errorf("Expected matching files to be valid, got: %v", err)
+	}
+
+	err := ValidateMixInputs([]string{first, otherRate, same, otherChannels})
+	if err == nil {
+		t.Fatal("Expected error for mismatched files")
+	}
+	for _, expected := range []string{first, otherRate, "48000 Hz", "44100 Hz", otherChannels, "2 channels"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected the error to contain %q, got: %v", expected, err)
+		}
+	}
+	if strings.Contains(err.Error(), same) {
+		t.Errorf("Expected the error to not mention the matching file, got: %v", err)
+	}
+
+	if err := ValidateMixInputs([]string{first, filepath.Join(dir, "missing.wav")}); err == nil {
+		t.Error("Expected error for a missing file")
+	}
+	if err := ValidateMixInputs(nil); err == nil {
+		t.Error("Expected error when no files are provided")
+	}
+}
+
+func TestInspectWav(t *testing.T) {
+	// test.wav has 21812 mono 16-bit frames at 44100 Hz
+	sampleRate, numChannels, bitDepth, numFrames, err := InspectWav("test.wav")
+	if err != nil {
+		t.Fatalf("Error in InspectWav: %v", err)
+	}
+	if sampleRate != 44100 || numChannels != 1 || bitDepth != 16 || numFrames != 21812 {
+		t.Errorf("Expected 44100 Hz, 1 channel, 16 bits and 21812 frames, got %d Hz, %d channels, %d bits and %d frames",
+			sampleRate, numChannels, bitDepth, numFrames)
+	}
+
+	filename := filepath.Join(t.TempDir(), "large.wav")
+	if err := SaveWavBitDepth(filename, make([]int16, 2*44100*3), 44100, 2, 24); err != nil {
+		t.Fatalf("Failed to save WAV file: %v", err)
+	}
+	// Cut off most of the sample data, so that only the header can give the number of frames
+	if err := os.Truncate(filename, 1024); err != nil {
+		t.Fatalf("Failed to truncate WAV file: %v", err)
+	}
+	sampleRate, numChannels, bitDepth, numFrames, err = InspectWav(filename)
+	if err != nil {
+		t.Fatalf("Error in InspectWav: %v", err)
+	}
+	if sampleRate != 44100 || numChannels != 2 || bitDepth != 24 || numFrames != 44100*3 {
+		t.Errorf("Expected 44100 Hz, 2 channels, 24 bits and %d frames, got %d Hz, %d channels, %d bits and %d frames",
+			44100*3, sampleRate, numChannels, bitDepth, numFrames)
+	}
+
+	if _, _, _, _, err := InspectWav("nonexistent.wav"); err == nil {
+		t.Error("Expected error for a missing file")
+	}
+}
+
+func BenchmarkInspectWav(b *testing.B) {
+	filename := filepath.Join(b.TempDir(), "large.wav")
+	if err := SaveWavBitDepth(filename, make([]int16, 2*44100*3), 44100, 2, 24); err != nil {
+		b.Fatalf("Failed to save WAV file: %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, _, _, err := InspectWav(filename); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoadWav(b *testing.B) {
+	filename := filepath.Join(b.TempDir(), "large.wav")
+	if err := SaveWavBitDepth(filename, make([]int16, 2*44100*3), 44100, 2, 24); err != nil {
+		b.Fatalf("Failed to save WAV file: %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := LoadWav(filename); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestFindPeakAcrossFiles(t *testing.T) {
+	dir := t.TempDir()
+	quiet := filepath.Join(dir, "quiet.wav")
+	loud := filepath.Join(dir, "loud.wav")
+
+	// The loud peak is far into the file, so that it is not in the first chunk
+	loudSamples := createSineWave(440, 1000, 44100, 2*peakChunkFrames+100)
+	loudSamples[2*peakChunkFrames+50] = -25000
+	quietSamples := createSineWave(440, 5000, 44100, 1000)
+	if err := SaveWav(quiet, quietSamples, 44100); err != nil {
+		t.Fatalf("Failed to save WAV file: %v", err)
+	}
+	if err := SaveWav(loud, loudSamples, 44100); err != nil {
+		t.Fatalf("Failed to save WAV file: %v", err)
+	}
+
+	peak, err := FindPeakAcrossFiles([]string{quiet, loud})
+	if err != nil {
+		t.Fatalf("Error in FindPeakAcrossFiles: %v", err)
+	}
+	if peak != 25000 {
+		t.Errorf("Expected a peak of 25000, got %d", peak)
+	}
+
+	peak, err = FindPeakAcrossFiles([]string{quiet})
+	if err != nil {
+		t.Fatalf("Error in FindPeakAcrossFiles: %v", err)
+	}
+	if expected := FindPeakAmplitude(quietSamples); peak != expected {
+		t.Errorf("Expected a peak of %d, got %d", expected, peak)
+	}
+
+	missing := filepath.Join(dir, "missing.wav")
+	if _, err := FindPeakAcrossFiles([]string{quiet, missing}); err == nil || !strings.Contains(err.Error(), missing) {
+		t.Errorf("Expected an error naming the missing file, got: %v", err)
+	}
+}